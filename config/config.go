@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -21,7 +24,40 @@ type Config struct {
 		Host                        string `json:"host"`
 		Port                        string `json:"port"`
 		ShowUnknownErrorsInResponse bool   `json:"showUnknownErrorsInResponse"`
+		// GatewayToken is the secret the API gateway sends with the identity it
+		// authenticated; without it every request is anonymous.
+		GatewayToken string `json:"gatewayToken"`
 	} `json:"server"`
+
+	Pricing struct {
+		BaseCurrency string `json:"baseCurrency"`
+		// PricesIncludeTax tells whether stored prices are gross amounts.
+		PricesIncludeTax bool `json:"pricesIncludeTax"`
+	} `json:"pricing"`
+
+	Localization struct {
+		// DefaultLocale is the locale of the name and description stored on
+		// products and categories themselves.
+		DefaultLocale string `json:"defaultLocale"`
+	} `json:"localization"`
+
+	Thumbnail struct {
+		// Address of the thumbnail service; empty disables thumbnail generation.
+		Address      string        `json:"address"`
+		Timeout      time.Duration `json:"timeout"`
+		Retries      int           `json:"retries"`
+		RetryBackoff time.Duration `json:"retryBackoff"`
+	} `json:"thumbnail"`
+
+	Jobs struct {
+		PurgeInterval  time.Duration `json:"purgeInterval"`
+		PurgeRetention time.Duration `json:"purgeRetention"`
+
+		ReservationReaperInterval   time.Duration `json:"reservationReaperInterval"`
+		LowStockInterval            time.Duration `json:"lowStockInterval"`
+		ScheduledPricesInterval     time.Duration `json:"scheduledPricesInterval"`
+		AvailabilityWindowsInterval time.Duration `json:"availabilityWindowsInterval"`
+	} `json:"jobs"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -48,12 +84,93 @@ func LoadConfig() (*Config, error) {
 			Host                        string `json:"host"`
 			Port                        string `json:"port"`
 			ShowUnknownErrorsInResponse bool   `json:"showUnknownErrorsInResponse"`
+			GatewayToken                string `json:"gatewayToken"`
 		}{
 			Host:                        os.Getenv("SERVER_HOST"),
 			Port:                        os.Getenv("SERVER_PORT"),
 			ShowUnknownErrorsInResponse: false,
+			GatewayToken:                os.Getenv("GATEWAY_TOKEN"),
 		},
 	}
 
+	cfg.Pricing.BaseCurrency = os.Getenv("BASE_CURRENCY")
+	if cfg.Pricing.BaseCurrency == "" {
+		cfg.Pricing.BaseCurrency = "RUB"
+	}
+
+	cfg.Localization.DefaultLocale = os.Getenv("DEFAULT_LOCALE")
+	if cfg.Localization.DefaultLocale == "" {
+		cfg.Localization.DefaultLocale = "ru"
+	}
+
+	var err error
+	if cfg.Pricing.PricesIncludeTax, err = getEnvBool("PRICES_INCLUDE_TAX", false); err != nil {
+		return nil, err
+	}
+	cfg.Thumbnail.Address = os.Getenv("THUMBNAIL_ADDRESS")
+	if cfg.Thumbnail.Timeout, err = getEnvDuration("THUMBNAIL_TIMEOUT", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Thumbnail.Retries, err = getEnvInt("THUMBNAIL_RETRIES", 3); err != nil {
+		return nil, err
+	}
+	if cfg.Thumbnail.RetryBackoff, err = getEnvDuration("THUMBNAIL_RETRY_BACKOFF", 200*time.Millisecond); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.PurgeInterval, err = getEnvDuration("PURGE_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.PurgeRetention, err = getEnvDuration("PURGE_RETENTION", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.ReservationReaperInterval, err = getEnvDuration("RESERVATION_REAPER_INTERVAL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.LowStockInterval, err = getEnvDuration("LOW_STOCK_INTERVAL", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.ScheduledPricesInterval, err = getEnvDuration("SCHEDULED_PRICES_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Jobs.AvailabilityWindowsInterval, err = getEnvDuration("AVAILABILITY_WINDOWS_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return d, nil
+}
+
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return b, nil
+}
+
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return i, nil
+}
@@ -0,0 +1,16 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+func (h *Handler) historyMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "GetProductHistory", func(ctx context.Context, req *models.GetProductHistoryInput) (*models.GetProductHistoryOutput, error) {
+			h.logger.Infof("Fetching history of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductHistory(ctx, req)
+		}),
+	}
+}
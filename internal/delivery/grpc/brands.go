@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+type brandRollupsRequest struct {
+	// Filter takes the same conditions as the x-filter metadata of GetProducts.
+	Filter         productFilterMetadata `json:"filter"`
+	IncludeDeleted bool                  `json:"include_deleted"`
+}
+
+func (h *Handler) brandMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "CreateBrand", func(ctx context.Context, req *models.CreateBrandInput) (*models.BrandOutput, error) {
+			h.logger.Infof("Creating brand: %s", req.Name)
+			return h.useCase.CreateBrand(ctx, req)
+		}),
+		catalogMethod(h, "GetBrand", func(ctx context.Context, req *models.GetBrandInput) (*models.BrandOutput, error) {
+			h.logger.Infof("Fetching brand with ID: %d", req.ID)
+			return h.useCase.GetBrand(ctx, req)
+		}),
+		catalogMethod(h, "UpdateBrand", func(ctx context.Context, req *models.UpdateBrandInput) (*models.BrandOutput, error) {
+			h.logger.Infof("Updating brand with ID: %d", req.ID)
+			return h.useCase.UpdateBrand(ctx, req)
+		}),
+		catalogMethod(h, "DeleteBrand", func(ctx context.Context, req *idRequest) (*emptyMessage, error) {
+			h.logger.Infof("Deleting brand with ID: %d", req.ID)
+			return &emptyMessage{}, h.useCase.DeleteBrand(ctx, req.ID)
+		}),
+		catalogMethod(h, "RestoreBrand", func(ctx context.Context, req *idRequest) (*models.BrandOutput, error) {
+			h.logger.Infof("Restoring brand with ID: %d", req.ID)
+			return h.useCase.RestoreBrand(ctx, req.ID)
+		}),
+		catalogMethod(h, "GetBrands", func(ctx context.Context, req *models.GetBrandsInput) (*models.GetBrandsOutput, error) {
+			h.logger.Infof("Fetching all brands.")
+			return h.useCase.GetBrands(ctx, req)
+		}),
+		catalogMethod(h, "GetBrandRollups", func(ctx context.Context, req *brandRollupsRequest) (*models.GetBrandRollupsOutput, error) {
+			h.logger.Infof("Fetching brand rollups.")
+			filter := models.ProductFilter{IncludeDeleted: req.IncludeDeleted}
+			req.Filter.apply(&filter)
+			return h.useCase.GetBrandRollups(ctx, &models.GetBrandRollupsInput{Filter: filter})
+		}),
+	}
+}
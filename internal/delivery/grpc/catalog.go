@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"encoding/json"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// catalogServiceName serves the catalog operations that ProductService in
+// products_protos has no RPCs for. Its messages are JSON objects, so clients
+// call it with the "json" content subtype (application/grpc+json).
+const catalogServiceName = "products.v1.CatalogService"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec encodes CatalogService messages as JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+// idRequest is the request of the CatalogService methods that only take the
+// ID of an entity.
+type idRequest struct {
+	ID int64 `json:"id"`
+}
+
+// emptyMessage is the request or response of the CatalogService methods
+// that take or return nothing.
+type emptyMessage struct{}
+
+// CatalogServiceDesc describes CatalogService; register it on the gRPC server
+// next to ProductService with the Handler as its implementation.
+func (h *Handler) CatalogServiceDesc() *grpc.ServiceDesc {
+	var methods []grpc.MethodDesc
+	methods = append(methods, h.reservationMethods()...)
+	methods = append(methods, h.historyMethods()...)
+	methods = append(methods, h.revisionMethods()...)
+	methods = append(methods, h.variantMethods()...)
+	methods = append(methods, h.stockMethods()...)
+	methods = append(methods, h.lowStockMethods()...)
+	methods = append(methods, h.promotionMethods()...)
+	methods = append(methods, h.brandMethods()...)
+	methods = append(methods, h.translationMethods()...)
+	methods = append(methods, h.relationMethods()...)
+	methods = append(methods, h.trashMethods()...)
+
+	return &grpc.ServiceDesc{
+		ServiceName: catalogServiceName,
+		HandlerType: (*any)(nil),
+		Methods:     methods,
+		Metadata:    "catalog.json",
+	}
+}
+
+// catalogMethod adapts call to a unary CatalogService method that decodes
+// its request into I and runs behind the server interceptors.
+func catalogMethod[I, O any](h *Handler, name string, call func(ctx context.Context, req *I) (O, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(I)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				response, err := call(ctx, req.(*I))
+				if err != nil {
+					h.logger.Errorf("Error in %s: %v", name, err)
+					return nil, err
+				}
+				return response, nil
+			}
+			if interceptor == nil {
+				return handler(ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{Server: h, FullMethod: "/" + catalogServiceName + "/" + name}
+			return interceptor(ctx, req, info, handler)
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"net"
+	"products/config"
+	repository "products/internal"
+	"products/internal/models"
+	useCase "products/internal/usecase"
+	"products/pkg/logger"
+	"products/pkg/reqctx"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// reservationRepository fakes the reservations of the repository; any other
+// repository method panics.
+type reservationRepository struct {
+	repository.Postgres
+
+	input *models.ReserveStockInput
+	actor string
+}
+
+func (r *reservationRepository) GetBundle(context.Context, int64) (*models.Bundle, error) {
+	return nil, nil
+}
+
+func (r *reservationRepository) ReserveStock(ctx context.Context, input *models.ReserveStockInput) (*models.Reservation, error) {
+	r.input = input
+	r.actor = reqctx.Actor(ctx)
+	return &models.Reservation{
+		ID:        "9b2f3c1e-8a4d-4e5f-9c6b-7d8e9f0a1b2c",
+		Status:    models.ReservationStatusHeld,
+		Items:     input.Items,
+		ExpiresAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+// dialCatalog serves CatalogService of a handler over repo in memory and
+// returns a connection to it.
+func dialCatalog(t *testing.T, repo repository.Postgres, gatewayToken string) *grpc.ClientConn {
+	t.Helper()
+
+	log := logger.NewApiLogger(&config.Config{})
+	require.NoError(t, log.InitLogger())
+	handler := NewHandler(useCase.NewUseCase(&config.Config{}, repo, nil, log), log)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(NewRequestContextInterceptor(gatewayToken)))
+	server.RegisterService(handler.CatalogServiceDesc(), handler)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype("json")))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestCatalogServiceReserveStock(t *testing.T) {
+	repo := &reservationRepository{}
+	conn := dialCatalog(t, repo, "secret")
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), gatewayTokenKey, "secret", actorKey, "checkout")
+	req := map[string]any{
+		"items":       []map[string]any{{"product_id": 7, "warehouse_id": 2, "quantity": 3}},
+		"ttl_seconds": 60,
+	}
+	var response map[string]any
+	err := conn.Invoke(ctx, "/"+catalogServiceName+"/ReserveStock", req, &response)
+	require.NoError(t, err)
+
+	require.NotNil(t, repo.input)
+	assert.Equal(t, time.Minute, repo.input.TTL)
+	assert.Equal(t, []*models.ReservationItem{{ProductID: 7, WarehouseID: 2, Quantity: 3}}, repo.input.Items)
+	assert.Equal(t, "checkout", repo.actor)
+	assert.Equal(t, map[string]any{
+		"id":         "9b2f3c1e-8a4d-4e5f-9c6b-7d8e9f0a1b2c",
+		"status":     "held",
+		"items":      []any{map[string]any{"product_id": 7.0, "warehouse_id": 2.0, "quantity": 3.0}},
+		"expires_at": "2024-05-01T12:00:00Z",
+	}, response["reservation"])
+}
+
+func TestCatalogServiceReturnsUseCaseErrors(t *testing.T) {
+	conn := dialCatalog(t, &reservationRepository{}, "")
+
+	var response map[string]any
+	err := conn.Invoke(context.Background(), "/"+catalogServiceName+"/ReserveStock", map[string]any{"items": []any{}}, &response)
+	assert.ErrorContains(t, err, "at least one item")
+}
@@ -1,11 +1,15 @@
 package grpc
 
 import (
+	"fmt"
 	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"products/internal/models"
 	useCase "products/internal/usecase"
 	"products/pkg/logger"
+	"strconv"
 )
 
 //go:generate ifacemaker -f handler.go -o ../../handler.go -i Handler -s Handler -p internal -y "Controller describes methods, implemented by the grpc package."
@@ -25,6 +29,7 @@ func (h *Handler) CreateProductCategory(ctx context.Context, req *productsv1.Cre
 	response, err := h.useCase.CreateProductCategory(ctx, &models.CreateProductCategoryInput{
 		Name:        req.Name,
 		Description: req.Description,
+		Slug:        metadataValue(ctx, slugKey),
 	})
 
 	if err != nil {
@@ -38,15 +43,34 @@ func (h *Handler) CreateProductCategory(ctx context.Context, req *productsv1.Cre
 }
 
 func (h *Handler) GetProductCategory(ctx context.Context, req *productsv1.GetProductCategoryRequest) (*productsv1.ProductCategoryResponse, error) {
-	h.logger.Infof("Fetching product category with ID: %d", req.Id)
+	input := models.GetProductCategoryInput{
+		ID:             req.Id,
+		IncludeDeleted: metadataBool(ctx, includeDeletedKey),
+	}
 
-	response, err := h.useCase.GetProductCategory(ctx, req.Id)
+	var response *models.GetProductCategoryOutput
+	var err error
+	if slug := metadataValue(ctx, slugKey); req.Id == 0 && slug != "" {
+		h.logger.Infof("Fetching product category with slug: %s", slug)
+		response, err = h.useCase.GetProductCategoryBySlug(ctx, &models.GetProductCategoryBySlugInput{Slug: slug, GetProductCategoryInput: input})
+	} else {
+		h.logger.Infof("Fetching product category with ID: %d", req.Id)
+		response, err = h.useCase.GetProductCategory(ctx, &input)
+	}
 
 	if err != nil {
 		h.logger.Errorf("Error fetching product category: %v", err)
 		return nil, err
 	}
 
+	header := metadata.Pairs(slugKey, response.Slug)
+	if response.Redirected {
+		header.Set(slugRedirectedKey, "true")
+	}
+	if err := grpc.SetHeader(ctx, header); err != nil {
+		h.logger.Errorf("Error setting response header: %v", err)
+	}
+
 	return &productsv1.ProductCategoryResponse{
 		Category: response.Category,
 	}, nil
@@ -59,6 +83,7 @@ func (h *Handler) UpdateProductCategory(ctx context.Context, req *productsv1.Upd
 		ID:          req.Id,
 		Name:        req.Name,
 		Description: req.Description,
+		Slug:        metadataValue(ctx, slugKey),
 	})
 
 	if err != nil {
@@ -74,7 +99,16 @@ func (h *Handler) UpdateProductCategory(ctx context.Context, req *productsv1.Upd
 func (h *Handler) DeleteProductCategory(ctx context.Context, req *productsv1.DeleteProductCategoryRequest) (*productsv1.DeleteProductCategoryResponse, error) {
 	h.logger.Infof("Deleting product category with ID: %d", req.Id)
 
-	err := h.useCase.DeleteProductCategory(ctx, req.Id)
+	reassignTo, err := metadataInt64(ctx, reassignCategoryIDKey)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.useCase.DeleteProductCategory(ctx, &models.DeleteProductCategoryInput{
+		ID:           req.Id,
+		Policy:       models.CategoryDeletePolicy(metadataValue(ctx, deletePolicyKey)),
+		ReassignToID: reassignTo,
+	})
 
 	if err != nil {
 		h.logger.Errorf("Error deleting product category: %v", err)
@@ -82,14 +116,16 @@ func (h *Handler) DeleteProductCategory(ctx context.Context, req *productsv1.Del
 	}
 
 	return &productsv1.DeleteProductCategoryResponse{
-		Message: "Product category deleted successfully.",
+		Message: fmt.Sprintf("Product category deleted successfully. Affected products: %d.", response.AffectedProducts),
 	}, nil
 }
 
 func (h *Handler) GetProductCategories(ctx context.Context, req *productsv1.GetProductCategoriesRequest) (*productsv1.GetProductCategoriesResponse, error) {
 	h.logger.Infof("Fetching all product categories.")
 
-	response, err := h.useCase.GetProductCategories(ctx)
+	response, err := h.useCase.GetProductCategories(ctx, &models.GetProductCategoriesInput{
+		IncludeDeleted: metadataBool(ctx, includeDeletedKey),
+	})
 
 	if err != nil {
 		h.logger.Errorf("Error fetching product categories: %v", err)
@@ -104,11 +140,31 @@ func (h *Handler) GetProductCategories(ctx context.Context, req *productsv1.GetP
 func (h *Handler) CreateProduct(ctx context.Context, req *productsv1.CreateProductRequest) (*productsv1.ProductResponse, error) {
 	h.logger.Infof("Creating product: %s", req.Name)
 
+	attributes, err := metadataAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	brandID, err := metadataInt64(ctx, brandIDKey)
+	if err != nil {
+		return nil, err
+	}
+	availabilityWindow, err := metadataAvailabilityWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if availabilityWindow == nil {
+		availabilityWindow = &models.AvailabilityWindow{}
+	}
+
 	response, err := h.useCase.CreateProduct(ctx, &models.CreateProductInput{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       float64(req.Price),
-		CategoryID:  req.CategoryId,
+		Name:               req.Name,
+		Description:        req.Description,
+		Price:              float64(req.Price),
+		CategoryID:         req.CategoryId,
+		Attributes:         attributes,
+		BrandID:            brandID,
+		AvailabilityWindow: *availabilityWindow,
+		Slug:               metadataValue(ctx, slugKey),
 	})
 
 	if err != nil {
@@ -122,15 +178,67 @@ func (h *Handler) CreateProduct(ctx context.Context, req *productsv1.CreateProdu
 }
 
 func (h *Handler) GetProduct(ctx context.Context, req *productsv1.GetProductRequest) (*productsv1.ProductResponse, error) {
-	h.logger.Infof("Fetching product with ID: %d", req.Id)
+	asOf, err := metadataTime(ctx, asOfKey)
+	if err != nil {
+		return nil, err
+	}
 
-	response, err := h.useCase.GetProduct(ctx, req.Id)
+	input := models.GetProductInput{
+		ID:                 req.Id,
+		IncludeDeleted:     metadataBool(ctx, includeDeletedKey),
+		IncludeUnpublished: metadataBool(ctx, includeUnpublishedKey),
+		IncludeVariants:    metadataBool(ctx, includeVariantsKey),
+		IncludeRelated:     metadataBool(ctx, includeRelatedKey),
+		AsOf:               asOf,
+		PriceList:          metadataValue(ctx, priceListKey),
+		Currency:           metadataValue(ctx, currencyKey),
+	}
+
+	var response *models.GetProductOutput
+	var variantID int64
+	if slug := metadataValue(ctx, slugKey); req.Id == 0 && slug != "" {
+		h.logger.Infof("Fetching product with slug: %s", slug)
+		response, err = h.useCase.GetProductBySlug(ctx, &models.GetProductBySlugInput{Slug: slug, GetProductInput: input})
+	} else if code := metadataValue(ctx, barcodeKey); req.Id == 0 && code != "" {
+		h.logger.Infof("Fetching product with barcode: %s", code)
+		var byBarcode *models.GetProductByBarcodeOutput
+		byBarcode, err = h.useCase.GetProductByBarcode(ctx, &models.GetProductByBarcodeInput{Barcode: code, GetProductInput: input})
+		if err == nil {
+			response, variantID = byBarcode.GetProductOutput, byBarcode.Barcode.VariantID
+		}
+	} else {
+		h.logger.Infof("Fetching product with ID: %d", req.Id)
+		response, err = h.useCase.GetProduct(ctx, &input)
+	}
 
 	if err != nil {
 		h.logger.Errorf("Error fetching product: %v", err)
 		return nil, err
 	}
 
+	header := metadata.Pairs(currencyKey, response.Currency, slugKey, response.Slug)
+	if response.Redirected {
+		header.Set(slugRedirectedKey, "true")
+	}
+	if response.BrandID != 0 {
+		header.Set(brandIDKey, strconv.FormatInt(response.BrandID, 10))
+	}
+	if variantID != 0 {
+		header.Set(variantIDKey, strconv.FormatInt(variantID, 10))
+	}
+	if !response.AvailabilityWindow.From.IsZero() || !response.AvailabilityWindow.Until.IsZero() {
+		header.Set(availabilityWindowKey, encodeAvailabilityWindow(response.AvailabilityWindow))
+	}
+	if input.IncludeVariants {
+		header.Set(variantsKey, encodeVariants(response.Variants))
+	}
+	if input.IncludeRelated {
+		header.Set(relatedKey, encodeRelatedProducts(response.Related))
+	}
+	if err := grpc.SetHeader(ctx, header); err != nil {
+		h.logger.Errorf("Error setting response header: %v", err)
+	}
+
 	return &productsv1.ProductResponse{
 		Product: response.Product,
 	}, nil
@@ -139,12 +247,31 @@ func (h *Handler) GetProduct(ctx context.Context, req *productsv1.GetProductRequ
 func (h *Handler) UpdateProduct(ctx context.Context, req *productsv1.UpdateProductRequest) (*productsv1.ProductResponse, error) {
 	h.logger.Infof("Updating product with ID: %d", req.Id)
 
+	attributes, err := metadataAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	brandID, err := metadataOptionalInt64(ctx, brandIDKey)
+	if err != nil {
+		return nil, err
+	}
+	availabilityWindow, err := metadataAvailabilityWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	response, err := h.useCase.UpdateProduct(ctx, &models.UpdateProductInput{
-		ID:          req.Id,
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       float64(req.Price),
-		CategoryID:  req.CategoryId,
+		ID:                 req.Id,
+		Name:               req.Name,
+		Description:        req.Description,
+		Price:              float64(req.Price),
+		CategoryID:         req.CategoryId,
+		Attributes:         attributes,
+		BrandID:            brandID,
+		AvailabilityWindow: availabilityWindow,
+		Slug:               metadataValue(ctx, slugKey),
+		Status:             models.ProductStatus(metadataValue(ctx, statusKey)),
+		StatusReason:       metadataValue(ctx, statusReasonKey),
 	})
 
 	if err != nil {
@@ -175,13 +302,31 @@ func (h *Handler) DeleteProduct(ctx context.Context, req *productsv1.DeleteProdu
 func (h *Handler) GetProducts(ctx context.Context, req *productsv1.GetProductsRequest) (*productsv1.GetProductsResponse, error) {
 	h.logger.Infof("Fetching all products.")
 
-	response, err := h.useCase.GetProducts(ctx)
+	filter, err := metadataProductFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	facets, err := metadataFacets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.useCase.GetProducts(ctx, &models.GetProductsInput{
+		ProductFilter: filter,
+		Facets:        facets,
+	})
 
 	if err != nil {
 		h.logger.Errorf("Error fetching products: %v", err)
 		return nil, err
 	}
 
+	if response.Facets != nil {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(facetsKey, encodeFacets(response.Facets))); err != nil {
+			h.logger.Errorf("Error setting response header: %v", err)
+		}
+	}
+
 	return &productsv1.GetProductsResponse{
 		Products: response.Products,
 	}, nil
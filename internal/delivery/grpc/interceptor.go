@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"products/internal/models"
+	"products/pkg/reqctx"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	actorKey         = "x-actor"
+	actorRoleKey     = "x-actor-role"
+	requestIDKey     = "x-request-id"
+	customerGroupKey = "x-customer-group"
+	gatewayTokenKey  = "x-gateway-token"
+	// acceptLanguageKey follows the HTTP header, e.g. "en-US,en;q=0.9,ru;q=0.5".
+	acceptLanguageKey = "accept-language"
+)
+
+// UnaryRequestContextInterceptor copies the caller identity and role, customer
+// group, accepted locales and request ID from incoming metadata into the
+// request context. A request ID is generated when the caller did not send one.
+func UnaryRequestContextInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	requestID := metadataValue(ctx, requestIDKey)
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+
+	ctx = reqctx.WithActor(ctx, metadataValue(ctx, actorKey))
+	role := metadataValue(ctx, actorRoleKey)
+	if role == models.ActorRoleSystem {
+		// The system role is reserved for background jobs.
+		role = ""
+	}
+	ctx = reqctx.WithRole(ctx, role)
+	ctx = reqctx.WithRequestID(ctx, requestID)
+	ctx = reqctx.WithCustomerGroup(ctx, metadataValue(ctx, customerGroupKey))
+	ctx = reqctx.WithLocales(ctx, parseAcceptLanguage(metadataValue(ctx, acceptLanguageKey)))
+
+	return handler(ctx, req)
+}
+
+// NewRequestContextInterceptor returns UnaryRequestContextInterceptor behind a
+// gateway check: the caller identity, role and customer group are dropped
+// unless the request carries the gateway token, so that only the gateway that
+// authenticated the caller can assert them. With an empty token every request
+// is anonymous.
+func NewRequestContextInterceptor(gatewayToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		token := metadataValue(ctx, gatewayTokenKey)
+		if gatewayToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(gatewayToken)) != 1 {
+			md = md.Copy()
+			md.Delete(actorKey)
+			md.Delete(actorRoleKey)
+			md.Delete(customerGroupKey)
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		return UnaryRequestContextInterceptor(ctx, req, info, handler)
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language value
+// ordered by quality, dropping the wildcard and tags with q=0.
+func parseAcceptLanguage(value string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(value, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	locales := make([]string, len(tags))
+	for i, tag := range tags {
+		locales[i] = tag.tag
+	}
+	return locales
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"products/pkg/reqctx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestContextInterceptorGatewayToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		gatewayToken string
+		sentToken    string
+		trusted      bool
+	}{
+		{name: "no token configured"},
+		{name: "no token configured, token sent", sentToken: "secret"},
+		{name: "matching token", gatewayToken: "secret", sentToken: "secret", trusted: true},
+		{name: "wrong token", gatewayToken: "secret", sentToken: "guess"},
+		{name: "missing token", gatewayToken: "secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.Pairs(actorKey, "alice", actorRoleKey, "admin", customerGroupKey, "wholesale", requestIDKey, "req-1")
+			if tt.sentToken != "" {
+				md.Set(gatewayTokenKey, tt.sentToken)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			var got context.Context
+			interceptor := NewRequestContextInterceptor(tt.gatewayToken)
+			_, err := interceptor(ctx, nil, nil, func(ctx context.Context, _ any) (any, error) {
+				got = ctx
+				return nil, nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "req-1", reqctx.RequestID(got))
+			if tt.trusted {
+				assert.Equal(t, "alice", reqctx.Actor(got))
+				assert.Equal(t, "admin", reqctx.Role(got))
+				assert.Equal(t, "wholesale", reqctx.CustomerGroup(got))
+			} else {
+				assert.Empty(t, reqctx.Actor(got))
+				assert.Empty(t, reqctx.Role(got))
+				assert.Empty(t, reqctx.CustomerGroup(got))
+			}
+		})
+	}
+}
+
+func TestRequestContextInterceptorDropsSystemRole(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(actorRoleKey, "system"))
+
+	_, err := UnaryRequestContextInterceptor(ctx, nil, nil, func(ctx context.Context, _ any) (any, error) {
+		assert.Empty(t, reqctx.Role(ctx))
+		assert.NotEmpty(t, reqctx.RequestID(ctx))
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+func (h *Handler) lowStockMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "SetProductReorderThreshold", func(ctx context.Context, req *models.SetReorderThresholdInput) (*emptyMessage, error) {
+			h.logger.Infof("Setting reorder threshold of product with ID: %d", req.ID)
+			return &emptyMessage{}, h.useCase.SetProductReorderThreshold(ctx, req)
+		}),
+		catalogMethod(h, "SetCategoryReorderThreshold", func(ctx context.Context, req *models.SetReorderThresholdInput) (*emptyMessage, error) {
+			h.logger.Infof("Setting reorder threshold of category with ID: %d", req.ID)
+			return &emptyMessage{}, h.useCase.SetCategoryReorderThreshold(ctx, req)
+		}),
+		catalogMethod(h, "ListLowStockProducts", func(ctx context.Context, req *models.ListLowStockProductsInput) (*models.ListLowStockProductsOutput, error) {
+			h.logger.Infof("Fetching low stock products.")
+			return h.useCase.ListLowStockProducts(ctx, req)
+		}),
+	}
+}
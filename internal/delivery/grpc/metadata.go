@@ -0,0 +1,345 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	"products/internal/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Request options that the products_protos messages have no fields for are
+// passed as incoming gRPC metadata.
+const (
+	deletePolicyKey       = "x-delete-policy"
+	reassignCategoryIDKey = "x-reassign-category-id"
+	includeDeletedKey     = "x-include-deleted"
+	includeUnpublishedKey = "x-include-unpublished"
+	asOfKey               = "x-as-of"
+	attributesKey         = "x-attributes"
+	filterKey             = "x-filter"
+	priceListKey          = "x-price-list"
+	currencyKey           = "x-currency"
+	brandIDKey            = "x-brand-id"
+	availabilityWindowKey = "x-availability-window"
+	slugKey               = "x-slug"
+	slugRedirectedKey     = "x-slug-redirected"
+	barcodeKey            = "x-barcode"
+	variantIDKey          = "x-variant-id"
+	includeVariantsKey    = "x-include-variants"
+	variantsKey           = "x-variants"
+	includeRelatedKey     = "x-include-related"
+	facetsKey             = "x-facets"
+	relatedKey            = "x-related"
+	statusKey             = "x-status"
+	statusReasonKey       = "x-status-reason"
+)
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func metadataInt64(ctx context.Context, key string) (int64, error) {
+	value := metadataValue(ctx, key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s metadata: %v", key, err)
+	}
+	return n, nil
+}
+
+// metadataOptionalInt64 is metadataInt64 that tells an absent key apart
+// from zero by returning nil.
+func metadataOptionalInt64(ctx context.Context, key string) (*int64, error) {
+	if metadataValue(ctx, key) == "" {
+		return nil, nil
+	}
+	n, err := metadataInt64(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func metadataBool(ctx context.Context, key string) bool {
+	value, err := strconv.ParseBool(metadataValue(ctx, key))
+	return err == nil && value
+}
+
+func metadataTime(ctx context.Context, key string) (time.Time, error) {
+	value := metadataValue(ctx, key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s metadata: %v", key, err)
+	}
+	return t, nil
+}
+
+// metadataAttributes decodes a JSON object of product attributes. It returns
+// nil when the key is absent.
+func metadataAttributes(ctx context.Context) (map[string]any, error) {
+	value := metadataValue(ctx, attributesKey)
+	if value == "" {
+		return nil, nil
+	}
+	var attributes map[string]any
+	if err := json.Unmarshal([]byte(value), &attributes); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %v", attributesKey, err)
+	}
+	return attributes, nil
+}
+
+type availabilityWindowMetadata struct {
+	From  *time.Time `json:"from,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// metadataAvailabilityWindow decodes a JSON availability window such as
+// {"from": "2024-12-01T00:00:00Z", "until": "2025-01-10T00:00:00Z"}. An
+// empty object clears the window; nil is returned when the key is absent.
+func metadataAvailabilityWindow(ctx context.Context) (*models.AvailabilityWindow, error) {
+	value := metadataValue(ctx, availabilityWindowKey)
+	if value == "" {
+		return nil, nil
+	}
+	var md availabilityWindowMetadata
+	if err := json.Unmarshal([]byte(value), &md); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %v", availabilityWindowKey, err)
+	}
+
+	var window models.AvailabilityWindow
+	if md.From != nil {
+		window.From = *md.From
+	}
+	if md.Until != nil {
+		window.Until = *md.Until
+	}
+	return &window, nil
+}
+
+// encodeAvailabilityWindow is the header form of an availability window.
+func encodeAvailabilityWindow(window models.AvailabilityWindow) string {
+	var md availabilityWindowMetadata
+	if !window.From.IsZero() {
+		md.From = &window.From
+	}
+	if !window.Until.IsZero() {
+		md.Until = &window.Until
+	}
+	b, _ := json.Marshal(md)
+	return string(b)
+}
+
+type variantMetadata struct {
+	ID         int64             `json:"id"`
+	SKU        string            `json:"sku"`
+	Price      *float64          `json:"price,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+	Attributes map[string]any    `json:"attributes,omitempty"`
+}
+
+// encodeVariants is the header form of the variants embedded in GetProduct.
+func encodeVariants(variants []*models.Variant) string {
+	md := make([]variantMetadata, len(variants))
+	for i, variant := range variants {
+		md[i] = variantMetadata{
+			ID:         variant.ID,
+			SKU:        variant.SKU,
+			Price:      variant.Price,
+			Options:    variant.Options,
+			Attributes: variant.Attributes,
+		}
+	}
+	b, _ := json.Marshal(md)
+	return asciiJSON(b)
+}
+
+type relatedProductMetadata struct {
+	Type        string  `json:"type"`
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float32 `json:"price"`
+	CategoryID  int64   `json:"category_id"`
+}
+
+// encodeRelatedProducts is the header form of the related products embedded
+// in GetProduct, a JSON array ordered by type and position.
+func encodeRelatedProducts(related []*models.RelatedProduct) string {
+	md := make([]relatedProductMetadata, len(related))
+	for i, item := range related {
+		md[i] = relatedProductMetadata{
+			Type:        string(item.Type),
+			ID:          item.Product.Id,
+			Name:        item.Product.Name,
+			Description: item.Product.Description,
+			Price:       item.Product.Price,
+			CategoryID:  item.Product.CategoryId,
+		}
+	}
+	b, _ := json.Marshal(md)
+	return asciiJSON(b)
+}
+
+// asciiJSON escapes non-ASCII characters of encoded JSON, since metadata
+// values must be printable ASCII.
+func asciiJSON(b []byte) string {
+	var sb strings.Builder
+	for _, r := range string(b) {
+		if r < utf8.RuneSelf {
+			sb.WriteRune(r)
+			continue
+		}
+		for _, unit := range utf16.Encode([]rune{r}) {
+			fmt.Fprintf(&sb, `\u%04x`, unit)
+		}
+	}
+	return sb.String()
+}
+
+type facetsRequestMetadata struct {
+	Attributes      []string `json:"attributes"`
+	PriceBucketSize float64  `json:"price_bucket_size"`
+}
+
+// metadataFacets decodes the facets requested with GetProducts, such as
+// {"attributes": ["color"], "price_bucket_size": 500}. It returns nil when
+// the key is absent.
+func metadataFacets(ctx context.Context) (*models.FacetsInput, error) {
+	value := metadataValue(ctx, facetsKey)
+	if value == "" {
+		return nil, nil
+	}
+	var md facetsRequestMetadata
+	if err := json.Unmarshal([]byte(value), &md); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %v", facetsKey, err)
+	}
+	return &models.FacetsInput{
+		AttributeCodes:  md.Attributes,
+		PriceBucketSize: md.PriceBucketSize,
+	}, nil
+}
+
+type facetValueMetadata struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type categoryFacetMetadata struct {
+	CategoryID int64 `json:"category_id"`
+	Count      int64 `json:"count"`
+}
+
+type brandFacetMetadata struct {
+	BrandID int64 `json:"brand_id"`
+	Count   int64 `json:"count"`
+}
+
+type attributeFacetMetadata struct {
+	Code   string               `json:"code"`
+	Values []facetValueMetadata `json:"values"`
+}
+
+type priceBucketMetadata struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int64   `json:"count"`
+}
+
+type facetsMetadata struct {
+	Total      int64                    `json:"total"`
+	Categories []categoryFacetMetadata  `json:"categories"`
+	Brands     []brandFacetMetadata     `json:"brands"`
+	Attributes []attributeFacetMetadata `json:"attributes"`
+	Prices     []priceBucketMetadata    `json:"prices"`
+}
+
+// encodeFacets is the header form of the facets counted with GetProducts.
+func encodeFacets(facets *models.GetProductFacetsOutput) string {
+	md := facetsMetadata{Total: facets.Total}
+	for _, facet := range facets.Categories {
+		md.Categories = append(md.Categories, categoryFacetMetadata{CategoryID: facet.CategoryID, Count: facet.Count})
+	}
+	for _, facet := range facets.Brands {
+		md.Brands = append(md.Brands, brandFacetMetadata{BrandID: facet.BrandID, Count: facet.Count})
+	}
+	for _, facet := range facets.Attributes {
+		attribute := attributeFacetMetadata{Code: facet.Code}
+		for _, value := range facet.Values {
+			attribute.Values = append(attribute.Values, facetValueMetadata{Value: value.Value, Count: value.Count})
+		}
+		md.Attributes = append(md.Attributes, attribute)
+	}
+	for _, bucket := range facets.Prices {
+		md.Prices = append(md.Prices, priceBucketMetadata{From: bucket.From, To: bucket.To, Count: bucket.Count})
+	}
+	b, _ := json.Marshal(md)
+	return asciiJSON(b)
+}
+
+type productFilterMetadata struct {
+	Query       string              `json:"query"`
+	Statuses    []string            `json:"statuses"`
+	CategoryIDs []int64             `json:"category_ids"`
+	BrandIDs    []int64             `json:"brand_ids"`
+	MinPrice    *float64            `json:"min_price"`
+	MaxPrice    *float64            `json:"max_price"`
+	Attributes  map[string][]string `json:"attributes"`
+}
+
+// metadataProductFilter decodes the JSON product filter sent with GetProducts.
+func metadataProductFilter(ctx context.Context) (models.ProductFilter, error) {
+	filter := models.ProductFilter{
+		IncludeDeleted: metadataBool(ctx, includeDeletedKey),
+	}
+	if metadataBool(ctx, includeUnpublishedKey) {
+		filter.Statuses = models.ProductStatuses
+	}
+
+	value := metadataValue(ctx, filterKey)
+	if value == "" {
+		return filter, nil
+	}
+	var md productFilterMetadata
+	if err := json.Unmarshal([]byte(value), &md); err != nil {
+		return filter, fmt.Errorf("invalid %s metadata: %v", filterKey, err)
+	}
+	md.apply(&filter)
+	return filter, nil
+}
+
+// apply copies the filter conditions onto filter; statuses replace the ones
+// already set only when given.
+func (md *productFilterMetadata) apply(filter *models.ProductFilter) {
+	filter.Query = md.Query
+	if len(md.Statuses) > 0 {
+		filter.Statuses = make([]models.ProductStatus, len(md.Statuses))
+		for i, status := range md.Statuses {
+			filter.Statuses[i] = models.ProductStatus(status)
+		}
+	}
+	filter.CategoryIDs = md.CategoryIDs
+	filter.BrandIDs = md.BrandIDs
+	filter.MinPrice = md.MinPrice
+	filter.MaxPrice = md.MaxPrice
+	filter.Attributes = md.Attributes
+}
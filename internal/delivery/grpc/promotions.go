@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+func (h *Handler) promotionMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "CreatePromotion", func(ctx context.Context, req *models.Promotion) (*models.PromotionOutput, error) {
+			h.logger.Infof("Creating promotion: %s", req.Name)
+			return h.useCase.CreatePromotion(ctx, req)
+		}),
+		catalogMethod(h, "UpdatePromotion", func(ctx context.Context, req *models.Promotion) (*models.PromotionOutput, error) {
+			h.logger.Infof("Updating promotion with ID: %d", req.ID)
+			return h.useCase.UpdatePromotion(ctx, req)
+		}),
+		catalogMethod(h, "DeletePromotion", func(ctx context.Context, req *idRequest) (*emptyMessage, error) {
+			h.logger.Infof("Deleting promotion with ID: %d", req.ID)
+			return &emptyMessage{}, h.useCase.DeletePromotion(ctx, req.ID)
+		}),
+		catalogMethod(h, "GetPromotions", func(ctx context.Context, _ *emptyMessage) (*models.GetPromotionsOutput, error) {
+			h.logger.Infof("Fetching all promotions.")
+			return h.useCase.GetPromotions(ctx)
+		}),
+		catalogMethod(h, "QuotePrices", func(ctx context.Context, req *models.QuotePricesInput) (*models.QuotePricesOutput, error) {
+			h.logger.Infof("Quoting prices for %d items", len(req.Items))
+			return h.useCase.QuotePrices(ctx, req)
+		}),
+	}
+}
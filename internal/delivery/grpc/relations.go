@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+type relationResponse struct {
+	Relation *models.ProductRelation `json:"relation"`
+}
+
+func (h *Handler) relationMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "AddProductRelation", func(ctx context.Context, req *models.AddProductRelationInput) (*relationResponse, error) {
+			h.logger.Infof("Relating product %d to product %d as %s", req.RelatedID, req.ProductID, req.Type)
+			relation, err := h.useCase.AddProductRelation(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &relationResponse{Relation: relation}, nil
+		}),
+		catalogMethod(h, "SetProductRelations", func(ctx context.Context, req *models.SetProductRelationsInput) (*models.GetProductRelationsOutput, error) {
+			h.logger.Infof("Setting %s relations of product with ID: %d", req.Type, req.ProductID)
+			return h.useCase.SetProductRelations(ctx, req)
+		}),
+		catalogMethod(h, "DeleteProductRelation", func(ctx context.Context, req *models.ProductRelation) (*emptyMessage, error) {
+			h.logger.Infof("Deleting %s relation from product %d to product %d", req.Type, req.ProductID, req.RelatedID)
+			return &emptyMessage{}, h.useCase.DeleteProductRelation(ctx, req)
+		}),
+		catalogMethod(h, "GetProductRelations", func(ctx context.Context, req *productRequest) (*models.GetProductRelationsOutput, error) {
+			h.logger.Infof("Fetching relations of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductRelations(ctx, req.ProductID)
+		}),
+	}
+}
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+	"time"
+)
+
+type reserveStockRequest struct {
+	Items []*models.ReservationItem `json:"items"`
+	// TTLSeconds is how long the hold lasts; zero takes the default.
+	TTLSeconds int64 `json:"ttl_seconds"`
+}
+
+type reservationRequest struct {
+	ID string `json:"id"`
+}
+
+func (h *Handler) reservationMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "ReserveStock", func(ctx context.Context, req *reserveStockRequest) (*models.ReservationOutput, error) {
+			h.logger.Infof("Reserving stock for %d items", len(req.Items))
+			return h.useCase.ReserveStock(ctx, &models.ReserveStockInput{
+				Items: req.Items,
+				TTL:   time.Duration(req.TTLSeconds) * time.Second,
+			})
+		}),
+		catalogMethod(h, "CommitReservation", func(ctx context.Context, req *reservationRequest) (*models.ReservationOutput, error) {
+			h.logger.Infof("Committing reservation %s", req.ID)
+			return h.useCase.CommitReservation(ctx, req.ID)
+		}),
+		catalogMethod(h, "ReleaseReservation", func(ctx context.Context, req *reservationRequest) (*models.ReservationOutput, error) {
+			h.logger.Infof("Releasing reservation %s", req.ID)
+			return h.useCase.ReleaseReservation(ctx, req.ID)
+		}),
+	}
+}
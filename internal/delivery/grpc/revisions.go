@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+type productRequest struct {
+	ProductID int64 `json:"product_id"`
+}
+
+func (h *Handler) revisionMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "GetProductRevisions", func(ctx context.Context, req *productRequest) (*models.GetProductRevisionsOutput, error) {
+			h.logger.Infof("Fetching revisions of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductRevisions(ctx, req.ProductID)
+		}),
+		catalogMethod(h, "DiffProductVersions", func(ctx context.Context, req *models.DiffProductVersionsInput) (*models.DiffProductVersionsOutput, error) {
+			h.logger.Infof("Comparing revisions %d and %d of product with ID: %d", req.FromRevision, req.ToRevision, req.ProductID)
+			return h.useCase.DiffProductVersions(ctx, req)
+		}),
+		catalogMethod(h, "RevertProduct", func(ctx context.Context, req *models.RevertProductInput) (*models.UpdateProductOutput, error) {
+			h.logger.Infof("Reverting product with ID %d to revision %d", req.ProductID, req.Revision)
+			return h.useCase.RevertProduct(ctx, req)
+		}),
+	}
+}
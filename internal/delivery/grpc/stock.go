@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+func (h *Handler) stockMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "CreateWarehouse", func(ctx context.Context, req *models.Warehouse) (*models.WarehouseOutput, error) {
+			h.logger.Infof("Creating warehouse: %s", req.Code)
+			return h.useCase.CreateWarehouse(ctx, req)
+		}),
+		catalogMethod(h, "UpdateWarehouse", func(ctx context.Context, req *models.Warehouse) (*models.WarehouseOutput, error) {
+			h.logger.Infof("Updating warehouse with ID: %d", req.ID)
+			return h.useCase.UpdateWarehouse(ctx, req)
+		}),
+		catalogMethod(h, "GetWarehouses", func(ctx context.Context, _ *emptyMessage) (*models.GetWarehousesOutput, error) {
+			h.logger.Infof("Fetching all warehouses.")
+			return h.useCase.GetWarehouses(ctx)
+		}),
+		catalogMethod(h, "AdjustStock", func(ctx context.Context, req *models.AdjustStockInput) (*models.AdjustStockOutput, error) {
+			h.logger.Infof("Adjusting stock of product %d in warehouse %d by %d", req.ProductID, req.WarehouseID, req.Delta)
+			return h.useCase.AdjustStock(ctx, req)
+		}),
+		catalogMethod(h, "GetProductAvailability", func(ctx context.Context, req *productRequest) (*models.GetProductAvailabilityOutput, error) {
+			h.logger.Infof("Fetching availability of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductAvailability(ctx, req.ProductID)
+		}),
+	}
+}
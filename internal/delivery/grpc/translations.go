@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+type deleteTranslationRequest struct {
+	ID     int64  `json:"id"`
+	Locale string `json:"locale"`
+}
+
+func (h *Handler) translationMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "SetProductTranslation", func(ctx context.Context, req *models.SetProductTranslationInput) (*models.TranslationOutput, error) {
+			h.logger.Infof("Setting %s translation of product with ID: %d", req.Locale, req.ProductID)
+			return h.useCase.SetProductTranslation(ctx, req)
+		}),
+		catalogMethod(h, "DeleteProductTranslation", func(ctx context.Context, req *deleteTranslationRequest) (*emptyMessage, error) {
+			h.logger.Infof("Deleting %s translation of product with ID: %d", req.Locale, req.ID)
+			return &emptyMessage{}, h.useCase.DeleteProductTranslation(ctx, req.ID, req.Locale)
+		}),
+		catalogMethod(h, "GetProductTranslations", func(ctx context.Context, req *productRequest) (*models.GetTranslationsOutput, error) {
+			h.logger.Infof("Fetching translations of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductTranslations(ctx, req.ProductID)
+		}),
+		catalogMethod(h, "SetCategoryTranslation", func(ctx context.Context, req *models.SetCategoryTranslationInput) (*models.TranslationOutput, error) {
+			h.logger.Infof("Setting %s translation of category with ID: %d", req.Locale, req.CategoryID)
+			return h.useCase.SetCategoryTranslation(ctx, req)
+		}),
+		catalogMethod(h, "DeleteCategoryTranslation", func(ctx context.Context, req *deleteTranslationRequest) (*emptyMessage, error) {
+			h.logger.Infof("Deleting %s translation of category with ID: %d", req.Locale, req.ID)
+			return &emptyMessage{}, h.useCase.DeleteCategoryTranslation(ctx, req.ID, req.Locale)
+		}),
+		catalogMethod(h, "GetCategoryTranslations", func(ctx context.Context, req *idRequest) (*models.GetTranslationsOutput, error) {
+			h.logger.Infof("Fetching translations of category with ID: %d", req.ID)
+			return h.useCase.GetCategoryTranslations(ctx, req.ID)
+		}),
+	}
+}
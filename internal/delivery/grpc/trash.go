@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+type productResponse struct {
+	Product *productsv1.Product `json:"product"`
+}
+
+type categoryResponse struct {
+	Category *productsv1.ProductCategory `json:"category"`
+}
+
+func (h *Handler) trashMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "RestoreProduct", func(ctx context.Context, req *idRequest) (*productResponse, error) {
+			h.logger.Infof("Restoring product with ID: %d", req.ID)
+			response, err := h.useCase.RestoreProduct(ctx, req.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &productResponse{Product: response.Product}, nil
+		}),
+		catalogMethod(h, "RestoreProductCategory", func(ctx context.Context, req *idRequest) (*categoryResponse, error) {
+			h.logger.Infof("Restoring product category with ID: %d", req.ID)
+			response, err := h.useCase.RestoreProductCategory(ctx, req.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &categoryResponse{Category: response.Category}, nil
+		}),
+	}
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"products/internal/models"
+)
+
+func (h *Handler) variantMethods() []grpc.MethodDesc {
+	return []grpc.MethodDesc{
+		catalogMethod(h, "SetProductOptionAxes", func(ctx context.Context, req *models.SetProductOptionAxesInput) (*models.GetProductOptionAxesOutput, error) {
+			h.logger.Infof("Setting option axes of product with ID: %d", req.ProductID)
+			return h.useCase.SetProductOptionAxes(ctx, req)
+		}),
+		catalogMethod(h, "GetProductOptionAxes", func(ctx context.Context, req *productRequest) (*models.GetProductOptionAxesOutput, error) {
+			h.logger.Infof("Fetching option axes of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductOptionAxes(ctx, req.ProductID)
+		}),
+		catalogMethod(h, "CreateVariant", func(ctx context.Context, req *models.CreateVariantInput) (*models.VariantOutput, error) {
+			h.logger.Infof("Creating variant %s of product with ID: %d", req.SKU, req.ProductID)
+			return h.useCase.CreateVariant(ctx, req)
+		}),
+		catalogMethod(h, "GetVariant", func(ctx context.Context, req *idRequest) (*models.VariantOutput, error) {
+			h.logger.Infof("Fetching variant with ID: %d", req.ID)
+			return h.useCase.GetVariant(ctx, req.ID)
+		}),
+		catalogMethod(h, "UpdateVariant", func(ctx context.Context, req *models.UpdateVariantInput) (*models.VariantOutput, error) {
+			h.logger.Infof("Updating variant with ID: %d", req.ID)
+			return h.useCase.UpdateVariant(ctx, req)
+		}),
+		catalogMethod(h, "DeleteVariant", func(ctx context.Context, req *idRequest) (*emptyMessage, error) {
+			h.logger.Infof("Deleting variant with ID: %d", req.ID)
+			return &emptyMessage{}, h.useCase.DeleteVariant(ctx, req.ID)
+		}),
+		catalogMethod(h, "GetProductVariants", func(ctx context.Context, req *productRequest) (*models.GetProductVariantsOutput, error) {
+			h.logger.Infof("Fetching variants of product with ID: %d", req.ProductID)
+			return h.useCase.GetProductVariants(ctx, req.ProductID)
+		}),
+	}
+}
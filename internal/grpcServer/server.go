@@ -1,6 +1,7 @@
 package grpcServer
 
 import (
+	"context"
 	"fmt"
 	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
 	"google.golang.org/grpc"
@@ -9,21 +10,27 @@ import (
 	"os/signal"
 	"products/config"
 	grpcHandler "products/internal/delivery/grpc"
+	"products/internal/jobs"
 	repository "products/internal/repository"
 	useCase "products/internal/usecase"
+	"products/pkg/events"
 	"products/pkg/logger"
 	storage "products/pkg/storage/postgres"
+	"products/pkg/thumbnail"
 )
 
 type Server struct {
 	grpcServer *grpc.Server
+	jobs       *jobs.Runner
+	thumbnails *thumbnail.Client
 	cfg        *config.Config
 	apiLogger  *logger.ApiLogger
 }
 
 func NewServer(cfg *config.Config, logger *logger.ApiLogger) *Server {
 	return &Server{
-		grpcServer: grpc.NewServer(),
+		grpcServer: grpc.NewServer(grpc.UnaryInterceptor(grpcHandler.NewRequestContextInterceptor(cfg.Server.GatewayToken))),
+		jobs:       jobs.NewRunner(logger),
 		cfg:        cfg,
 		apiLogger:  logger,
 	}
@@ -35,10 +42,35 @@ func (s *Server) MapHandlers(logger *logger.ApiLogger) error {
 		return err
 	}
 	repo := repository.NewPostgresRepository(db, logger)
-	useCase := useCase.NewUseCase(repo, logger)
+
+	var thumbnails useCase.ThumbnailGenerator
+	if s.cfg.Thumbnail.Address != "" {
+		client, err := thumbnail.NewClient(s.cfg)
+		if err != nil {
+			return err
+		}
+		s.thumbnails = client
+		thumbnails = client
+	} else {
+		logger.Info("Thumbnail service is not configured, media is stored without thumbnails")
+	}
+
+	if s.cfg.Server.GatewayToken == "" {
+		logger.Warn("Gateway token is not configured, caller identity, role and customer group are ignored")
+	}
+
+	useCase := useCase.NewUseCase(s.cfg, repo, thumbnails, logger)
 	handler := grpcHandler.NewHandler(useCase, logger)
+	publisher := events.NewLogPublisher(logger)
 
 	productsv1.RegisterProductServiceServer(s.grpcServer, handler)
+	s.grpcServer.RegisterService(handler.CatalogServiceDesc(), handler)
+
+	s.jobs.Add(jobs.NewPurgeJob(useCase, s.cfg.Jobs.PurgeRetention, logger), s.cfg.Jobs.PurgeInterval)
+	s.jobs.Add(jobs.NewReservationReaperJob(useCase, logger), s.cfg.Jobs.ReservationReaperInterval)
+	s.jobs.Add(jobs.NewLowStockJob(useCase, publisher, logger), s.cfg.Jobs.LowStockInterval)
+	s.jobs.Add(jobs.NewScheduledPricesJob(useCase, logger), s.cfg.Jobs.ScheduledPricesInterval)
+	s.jobs.Add(jobs.NewAvailabilityWindowsJob(useCase, publisher, logger), s.cfg.Jobs.AvailabilityWindowsInterval)
 
 	return nil
 }
@@ -52,6 +84,10 @@ func (s *Server) Run() error {
 
 	s.apiLogger.Infof("Starting GRPC server on port %s", s.cfg.Server.Port)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.jobs.Start(ctx)
+
 	go func() {
 		if err := s.grpcServer.Serve(listener); err != nil {
 			s.apiLogger.Fatalf("Failed to serve GRPC server: %v", err)
@@ -64,6 +100,13 @@ func (s *Server) Run() error {
 
 	s.apiLogger.Info("Shutting down GRPC server gracefully...")
 	s.grpcServer.GracefulStop()
+	cancel()
+	s.jobs.Wait()
+	if s.thumbnails != nil {
+		if err := s.thumbnails.Close(); err != nil {
+			s.apiLogger.Errorf("Error closing thumbnail client: %v", err)
+		}
+	}
 	s.apiLogger.Info("Server gracefully stopped")
 
 	return nil
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"golang.org/x/net/context"
+	useCase "products/internal/usecase"
+	"products/pkg/events"
+	"products/pkg/logger"
+)
+
+const ProductStatusChangedEventType = "product.status_changed"
+
+// AvailabilityWindowsJob discontinues products whose availability window has closed
+// and republishes them when a new window opens, emitting an event per change.
+type AvailabilityWindowsJob struct {
+	useCase   *useCase.UseCase
+	publisher events.Publisher
+	logger    *logger.ApiLogger
+}
+
+func NewAvailabilityWindowsJob(useCase *useCase.UseCase, publisher events.Publisher, logger *logger.ApiLogger) *AvailabilityWindowsJob {
+	return &AvailabilityWindowsJob{useCase: useCase, publisher: publisher, logger: logger}
+}
+
+func (j *AvailabilityWindowsJob) Name() string {
+	return "availability-windows"
+}
+
+func (j *AvailabilityWindowsJob) Run(ctx context.Context) error {
+	output, err := j.useCase.ApplyAvailabilityWindows(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, transition := range output.Transitions {
+		if err = j.publisher.Publish(ctx, events.Event{Type: ProductStatusChangedEventType, Payload: transition}); err != nil {
+			j.logger.Errorf("Error publishing status change event for product %d: %v", transition.ProductID, err)
+		}
+	}
+	return nil
+}
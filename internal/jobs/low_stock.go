@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"golang.org/x/net/context"
+	useCase "products/internal/usecase"
+	"products/pkg/events"
+	"products/pkg/logger"
+)
+
+const LowStockEventType = "stock.low"
+
+// LowStockJob emits an event for every stock level that fell below its reorder threshold.
+type LowStockJob struct {
+	useCase   *useCase.UseCase
+	publisher events.Publisher
+	logger    *logger.ApiLogger
+}
+
+func NewLowStockJob(useCase *useCase.UseCase, publisher events.Publisher, logger *logger.ApiLogger) *LowStockJob {
+	return &LowStockJob{useCase: useCase, publisher: publisher, logger: logger}
+}
+
+func (j *LowStockJob) Name() string {
+	return "low-stock-evaluator"
+}
+
+func (j *LowStockJob) Run(ctx context.Context) error {
+	output, err := j.useCase.EvaluateLowStock(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range output.Crossed {
+		if err = j.publisher.Publish(ctx, events.Event{Type: LowStockEventType, Payload: item}); err != nil {
+			j.logger.Errorf("Error publishing low stock event for product %d: %v", item.ProductID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"golang.org/x/net/context"
+	useCase "products/internal/usecase"
+	"products/pkg/logger"
+	"time"
+)
+
+// PurgeJob permanently removes items that stayed soft-deleted longer than the retention period.
+type PurgeJob struct {
+	useCase   *useCase.UseCase
+	retention time.Duration
+	logger    *logger.ApiLogger
+}
+
+func NewPurgeJob(useCase *useCase.UseCase, retention time.Duration, logger *logger.ApiLogger) *PurgeJob {
+	return &PurgeJob{useCase: useCase, retention: retention, logger: logger}
+}
+
+func (j *PurgeJob) Name() string {
+	return "purge-deleted"
+}
+
+func (j *PurgeJob) Run(ctx context.Context) error {
+	output, err := j.useCase.PurgeDeleted(ctx, j.retention)
+	if err != nil {
+		return err
+	}
+	if output.Products > 0 || output.Categories > 0 {
+		j.logger.Infof("Purged %d products and %d product categories", output.Products, output.Categories)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"golang.org/x/net/context"
+	useCase "products/internal/usecase"
+	"products/pkg/logger"
+)
+
+// ReservationReaperJob releases stock held by expired reservations.
+type ReservationReaperJob struct {
+	useCase *useCase.UseCase
+	logger  *logger.ApiLogger
+}
+
+func NewReservationReaperJob(useCase *useCase.UseCase, logger *logger.ApiLogger) *ReservationReaperJob {
+	return &ReservationReaperJob{useCase: useCase, logger: logger}
+}
+
+func (j *ReservationReaperJob) Name() string {
+	return "reservation-reaper"
+}
+
+func (j *ReservationReaperJob) Run(ctx context.Context) error {
+	output, err := j.useCase.ReleaseExpiredReservations(ctx)
+	if err != nil {
+		return err
+	}
+	if output.Released > 0 {
+		j.logger.Infof("Released %d expired stock reservations", output.Released)
+	}
+	return nil
+}
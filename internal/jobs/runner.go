@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"golang.org/x/net/context"
+	"products/pkg/logger"
+	"sync"
+	"time"
+)
+
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+}
+
+// Runner executes registered jobs periodically until its context is cancelled.
+type Runner struct {
+	jobs   []scheduledJob
+	logger *logger.ApiLogger
+	wg     sync.WaitGroup
+}
+
+func NewRunner(logger *logger.ApiLogger) *Runner {
+	return &Runner{logger: logger}
+}
+
+// Add registers a job. Jobs with a non-positive interval are disabled.
+func (r *Runner) Add(job Job, interval time.Duration) {
+	if interval <= 0 {
+		r.logger.Infof("Job %s is disabled", job.Name())
+		return
+	}
+	r.jobs = append(r.jobs, scheduledJob{job: job, interval: interval})
+}
+
+func (r *Runner) Start(ctx context.Context) {
+	for _, j := range r.jobs {
+		r.wg.Add(1)
+		go r.loop(ctx, j)
+	}
+}
+
+// Wait blocks until all job loops have returned.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, j scheduledJob) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.job.Run(ctx); err != nil {
+				r.logger.Errorf("Job %s failed: %v", j.job.Name(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"golang.org/x/net/context"
+	useCase "products/internal/usecase"
+	"products/pkg/logger"
+)
+
+// ScheduledPricesJob applies scheduled price changes and restores prices when
+// their window ends. Replicas that miss the scheduler lock skip the run.
+type ScheduledPricesJob struct {
+	useCase *useCase.UseCase
+	logger  *logger.ApiLogger
+}
+
+func NewScheduledPricesJob(useCase *useCase.UseCase, logger *logger.ApiLogger) *ScheduledPricesJob {
+	return &ScheduledPricesJob{useCase: useCase, logger: logger}
+}
+
+func (j *ScheduledPricesJob) Name() string {
+	return "scheduled-prices"
+}
+
+func (j *ScheduledPricesJob) Run(ctx context.Context) error {
+	output, err := j.useCase.ApplyScheduledPrices(ctx)
+	if err != nil {
+		return err
+	}
+	if output.Applied > 0 || output.Reverted > 0 {
+		j.logger.Infof("Applied %d scheduled prices and reverted %d", output.Applied, output.Reverted)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeEnum    AttributeType = "enum"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// AttributeDefinition describes one typed attribute in a category's schema.
+type AttributeDefinition struct {
+	ID         int64
+	CategoryID int64
+	Code       string
+	Name       string
+	Type       AttributeType
+	// Unit is shown next to number values, e.g. "in" or "V".
+	Unit     string
+	Required bool
+	// Min and Max bound number values.
+	Min        *float64
+	Max        *float64
+	EnumValues []string
+}
+
+type AttributeDefinitionOutput struct {
+	Attribute *AttributeDefinition
+}
+
+type GetCategoryAttributesOutput struct {
+	Attributes []*AttributeDefinition
+}
+
+type GetProductAttributesOutput struct {
+	Attributes map[string]any
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityProduct         = "product"
+	AuditEntityProductCategory = "product_category"
+	AuditEntityProductVariant  = "product_variant"
+	AuditEntityBrand           = "brand"
+	// AuditEntityPriceListPrice records are keyed by product ID; the
+	// snapshots carry the price list ID.
+	AuditEntityPriceListPrice = "price_list_price"
+	// Translation records are keyed by product or category ID; the snapshots
+	// carry the locale.
+	AuditEntityProductTranslation  = "product_translation"
+	AuditEntityCategoryTranslation = "category_translation"
+)
+
+type AuditOperation string
+
+const (
+	AuditOperationCreate  AuditOperation = "create"
+	AuditOperationUpdate  AuditOperation = "update"
+	AuditOperationDelete  AuditOperation = "delete"
+	AuditOperationRestore AuditOperation = "restore"
+)
+
+type AuditRecord struct {
+	ID         int64           `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	Operation  AuditOperation  `json:"operation"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type GetProductHistoryInput struct {
+	ProductID int64 `json:"product_id"`
+	// From and To bound the record creation time; zero values leave the range open.
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Limit  int64     `json:"limit"`
+	Offset int64     `json:"offset"`
+}
+
+type GetProductHistoryOutput struct {
+	Records []*AuditRecord `json:"records"`
+	Total   int64          `json:"total"`
+}
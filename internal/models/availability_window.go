@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AvailabilityWindow limits when a product is sold. A zero bound leaves that
+// side of the window open.
+type AvailabilityWindow struct {
+	From  time.Time
+	Until time.Time
+}
+
+// ApplyAvailabilityWindowsOutput lists the status transitions made because a
+// product's availability window opened or closed.
+type ApplyAvailabilityWindowsOutput struct {
+	Transitions []*ProductTransition
+}
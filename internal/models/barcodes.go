@@ -0,0 +1,67 @@
+package models
+
+import "products/pkg/barcode"
+
+type Barcode struct {
+	ID     int64
+	Code   string
+	Format barcode.Format
+	// GTIN is the code padded to 14 digits; it is unique across the catalog.
+	GTIN      string
+	ProductID int64
+	// VariantID is zero for barcodes of the product itself.
+	VariantID int64
+}
+
+type AddBarcodeInput struct {
+	ProductID int64
+	VariantID int64
+	Code      string
+}
+
+type BarcodeOutput struct {
+	Barcode *Barcode
+}
+
+type GetBarcodesOutput struct {
+	Barcodes []*Barcode
+}
+
+type ImportBarcodesInput struct {
+	Rows []*AddBarcodeInput
+}
+
+// BarcodeImportError is the reason a row of a barcode import was skipped.
+// Row is the zero-based index of the row in the input.
+type BarcodeImportError struct {
+	Row   int
+	Code  string
+	Error string
+}
+
+// ParsedBarcodeRow is a row of a barcode import whose code has been parsed.
+// Row is the zero-based index of the row in the input.
+type ParsedBarcodeRow struct {
+	Row    int
+	Input  *AddBarcodeInput
+	Parsed *barcode.Barcode
+}
+
+type ImportBarcodesOutput struct {
+	Barcodes []*Barcode
+	Errors   []*BarcodeImportError
+}
+
+// GetProductByBarcodeInput looks a product up by any barcode of it or of
+// one of its variants.
+type GetProductByBarcodeInput struct {
+	Barcode string
+	GetProductInput
+}
+
+type GetProductByBarcodeOutput struct {
+	*GetProductOutput
+	Barcode *Barcode
+	// Variant is set when the barcode belongs to a variant.
+	Variant *Variant
+}
@@ -0,0 +1,54 @@
+package models
+
+type Brand struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CreateBrandInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type GetBrandInput struct {
+	ID             int64 `json:"id"`
+	IncludeDeleted bool  `json:"include_deleted"`
+}
+
+type UpdateBrandInput struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type BrandOutput struct {
+	Brand *Brand `json:"brand"`
+}
+
+type GetBrandsInput struct {
+	IncludeDeleted bool `json:"include_deleted"`
+}
+
+type GetBrandsOutput struct {
+	Brands []*Brand `json:"brands"`
+}
+
+// BrandRollup summarizes the live products of a brand.
+type BrandRollup struct {
+	BrandID      int64   `json:"brand_id"`
+	Name         string  `json:"name"`
+	ProductCount int64   `json:"product_count"`
+	MinPrice     float64 `json:"min_price"`
+	MaxPrice     float64 `json:"max_price"`
+	AvgPrice     float64 `json:"avg_price"`
+}
+
+type GetBrandRollupsInput struct {
+	// Filter narrows the products counted; its BrandIDs select the brands.
+	Filter ProductFilter
+}
+
+type GetBrandRollupsOutput struct {
+	Rollups []*BrandRollup `json:"rollups"`
+}
@@ -0,0 +1,29 @@
+package models
+
+type BundlePricing string
+
+const (
+	// BundlePricingFixed sells the bundle at its own product price.
+	BundlePricingFixed BundlePricing = "fixed"
+	// BundlePricingComponents sells the bundle at the sum of its component
+	// prices less DiscountPercent.
+	BundlePricingComponents BundlePricing = "components"
+)
+
+type BundleComponent struct {
+	ProductID int64
+	Quantity  int64
+}
+
+// Bundle makes a product a kit of other products. Components cannot be
+// bundles themselves.
+type Bundle struct {
+	ProductID       int64
+	Pricing         BundlePricing
+	DiscountPercent float64
+	Components      []*BundleComponent
+}
+
+type BundleOutput struct {
+	Bundle *Bundle
+}
@@ -0,0 +1,27 @@
+package models
+
+// CustomerGroup is a sales channel such as wholesale, retail or employees.
+type CustomerGroup struct {
+	ID   int64
+	Code string
+	Name string
+	// PriceListID is the price list whose prices the group sees; zero means base prices.
+	PriceListID int64
+}
+
+type CustomerGroupOutput struct {
+	CustomerGroup *CustomerGroup
+}
+
+type GetCustomerGroupsOutput struct {
+	CustomerGroups []*CustomerGroup
+}
+
+// SetProductVisibilityInput restricts a product to the listed customer
+// groups, or lifts the restriction when Restricted is false.
+type SetProductVisibilityInput struct {
+	ProductID  int64
+	Restricted bool
+	// GroupIDs are the customer groups that see the restricted product.
+	GroupIDs []int64
+}
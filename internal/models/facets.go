@@ -0,0 +1,46 @@
+package models
+
+type FacetsInput struct {
+	// AttributeCodes lists the attributes to count values for.
+	AttributeCodes  []string
+	PriceBucketSize float64
+}
+
+type GetProductFacetsInput struct {
+	Filter ProductFilter
+	FacetsInput
+}
+
+type FacetValue struct {
+	Value string
+	Count int64
+}
+
+type AttributeFacet struct {
+	Code   string
+	Values []*FacetValue
+}
+
+type CategoryFacet struct {
+	CategoryID int64
+	Count      int64
+}
+
+type BrandFacet struct {
+	BrandID int64
+	Count   int64
+}
+
+type PriceBucket struct {
+	From  float64
+	To    float64
+	Count int64
+}
+
+type GetProductFacetsOutput struct {
+	Total      int64
+	Categories []*CategoryFacet
+	Brands     []*BrandFacet
+	Attributes []*AttributeFacet
+	Prices     []*PriceBucket
+}
@@ -0,0 +1,31 @@
+package models
+
+type SetReorderThresholdInput struct {
+	// ID is a product or category ID, depending on the call.
+	ID int64 `json:"id"`
+	// Threshold clears the threshold when nil.
+	Threshold *int64 `json:"threshold"`
+}
+
+type LowStockItem struct {
+	ProductID   int64 `json:"product_id"`
+	WarehouseID int64 `json:"warehouse_id"`
+	Available   int64 `json:"available"`
+	Threshold   int64 `json:"threshold"`
+}
+
+type ListLowStockProductsInput struct {
+	WarehouseIDs []int64 `json:"warehouse_ids"`
+	Limit        int64   `json:"limit"`
+	Offset       int64   `json:"offset"`
+}
+
+type ListLowStockProductsOutput struct {
+	Items []*LowStockItem `json:"items"`
+	Total int64           `json:"total"`
+}
+
+type EvaluateLowStockOutput struct {
+	// Crossed lists stock levels that fell below their threshold since the last run.
+	Crossed []*LowStockItem
+}
@@ -0,0 +1,44 @@
+package models
+
+type MediaKind string
+
+const MediaKindImage MediaKind = "image"
+
+// Media is an entry of a product's gallery. Position orders the gallery from 1.
+type Media struct {
+	ID        int64
+	ProductID int64
+	Kind      MediaKind
+	URL       string
+	AltText   string
+	Position  int64
+	Primary   bool
+	// ThumbnailRef identifies the stored thumbnail; empty when none was generated.
+	ThumbnailRef string
+}
+
+type AddProductMediaInput struct {
+	ProductID int64
+	URL       string
+	AltText   string
+	// Primary makes the image the product's primary image. The first image
+	// of a gallery is always primary.
+	Primary bool
+	// Thumbnail and ThumbnailRef are filled in by the usecase.
+	Thumbnail    []byte
+	ThumbnailRef string
+}
+
+type MediaOutput struct {
+	Media *Media
+}
+
+type GetProductMediaOutput struct {
+	Media []*Media
+}
+
+type ReorderProductMediaInput struct {
+	ProductID int64
+	// MediaIDs lists every media ID of the product in the new order.
+	MediaIDs []int64
+}
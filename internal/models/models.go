@@ -1,10 +1,23 @@
 package models
 
-import productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+import (
+	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+	"time"
+)
 
 type CreateProductCategoryInput struct {
 	Name        string
 	Description string
+	// Slug is the URL key; it is generated from the name when empty.
+	Slug string
+}
+
+type GetProductCategoryInput struct {
+	ID             int64
+	IncludeDeleted bool
+	// Locales selects translated content, most preferred first; the default
+	// locale content is used when none of them has a translation.
+	Locales []string
 }
 
 type CreateProductCategoryOutput struct {
@@ -13,18 +26,55 @@ type CreateProductCategoryOutput struct {
 
 type GetProductCategoryOutput struct {
 	Category *productsv1.ProductCategory
+	Slug     string
+	// Redirected tells that the category was found by a former slug.
+	Redirected bool
 }
 
 type UpdateProductCategoryInput struct {
 	ID          int64
 	Name        string
 	Description string
+	// Slug replaces the URL key when set; otherwise a new one is generated
+	// when the name changes. The former slug keeps redirecting.
+	Slug string
 }
 
 type UpdateProductCategoryOutput struct {
 	Category *productsv1.ProductCategory
 }
 
+type CategoryDeletePolicy string
+
+const (
+	CategoryDeletePolicyRestrict CategoryDeletePolicy = "restrict"
+	CategoryDeletePolicyReassign CategoryDeletePolicy = "reassign"
+	CategoryDeletePolicyCascade  CategoryDeletePolicy = "cascade"
+	CategoryDeletePolicyArchive  CategoryDeletePolicy = "archive"
+	CategoryDeletePolicyNullify  CategoryDeletePolicy = "nullify"
+)
+
+type DeleteProductCategoryInput struct {
+	ID     int64
+	Policy CategoryDeletePolicy
+	// ReassignToID is the target category for CategoryDeletePolicyReassign.
+	ReassignToID int64
+	// ArchiveFrom lists the statuses the caller may archive a product from
+	// under CategoryDeletePolicyArchive.
+	ArchiveFrom []ProductStatus
+}
+
+type DeleteProductCategoryOutput struct {
+	AffectedProducts int64
+}
+
+type GetProductCategoriesInput struct {
+	IncludeDeleted bool
+	// Locales selects translated content, most preferred first; the default
+	// locale content is used when none of them has a translation.
+	Locales []string
+}
+
 type GetProductCategoriesOutput struct {
 	Categories []*productsv1.ProductCategory
 }
@@ -34,14 +84,48 @@ type CreateProductInput struct {
 	Description string
 	Price       float64
 	CategoryID  int64
+	// Attributes are validated against the category's attribute schema.
+	Attributes         map[string]any
+	BrandID            int64
+	AvailabilityWindow AvailabilityWindow
+	// Slug is the URL key; it is generated from the name when empty.
+	Slug string
 }
 
 type CreateProductOutput struct {
 	Product *productsv1.Product
 }
 
+type GetProductInput struct {
+	ID             int64
+	IncludeDeleted bool
+	// IncludeUnpublished returns the product whatever its status and
+	// availability window; storefront reads only see published products
+	// inside their window.
+	IncludeUnpublished bool
+	// Locales selects translated content, most preferred first; the default
+	// locale content is used when none of them has a translation.
+	Locales []string
+	// AsOf reads the product as it was at the given time when non-zero.
+	AsOf            time.Time
+	IncludeVariants bool
+	// IncludeRelated embeds the related products visible to the reader.
+	IncludeRelated bool
+	// PriceList or Currency select the price returned in Product.Price.
+	PriceList string
+	Currency  string
+}
+
 type GetProductOutput struct {
-	Product *productsv1.Product
+	Product            *productsv1.Product
+	BrandID            int64
+	AvailabilityWindow AvailabilityWindow
+	Currency           string
+	Variants           []*Variant
+	Related            []*RelatedProduct
+	Slug               string
+	// Redirected tells that the product was found by a former slug.
+	Redirected bool
 }
 
 type UpdateProductInput struct {
@@ -50,12 +134,64 @@ type UpdateProductInput struct {
 	Description string
 	Price       float64
 	CategoryID  int64
+	// Attributes replace the stored attributes; nil keeps them.
+	Attributes map[string]any
+	// BrandID replaces the brand when set; zero clears it and nil keeps it.
+	BrandID *int64
+	// AvailabilityWindow replaces the availability window when set; nil keeps it.
+	AvailabilityWindow *AvailabilityWindow
+	// Slug replaces the URL key when set; otherwise a new one is generated
+	// when the name changes. The former slug keeps redirecting.
+	Slug string
+	// Status moves the product along the status workflow after the update
+	// when set and different from the current status.
+	Status       ProductStatus
+	StatusReason string
 }
 
 type UpdateProductOutput struct {
-	Product *productsv1.Product
+	Product *productsv1.Product `json:"product"`
+}
+
+// ProductFilter narrows product listings and facet counts.
+type ProductFilter struct {
+	IncludeDeleted bool
+	// Statuses keeps products in any of the listed statuses; empty keeps
+	// published products inside their availability window only.
+	Statuses []ProductStatus
+	// Query matches product names and descriptions case-insensitively, in the
+	// default locale and in any of Locales.
+	Query string
+	// Locales selects translated content, most preferred first.
+	Locales     []string
+	CategoryIDs []int64
+	BrandIDs    []int64
+	MinPrice    *float64
+	MaxPrice    *float64
+	// Attributes keeps products whose attribute equals any of the listed values.
+	Attributes map[string][]string
+	// CustomerGroupID is the caller's customer group. Restricted products are
+	// dropped unless the group may see them or IncludeRestricted is set.
+	CustomerGroupID   int64
+	IncludeRestricted bool
+	// PriceListID prices products for MinPrice, MaxPrice and the price facet;
+	// zero uses base prices. BaseCurrency is the currency of base prices.
+	PriceListID  int64
+	BaseCurrency string
+}
+
+type GetProductsInput struct {
+	ProductFilter
+	// Facets counts facets over the same filter when set.
+	Facets *FacetsInput
 }
 
 type GetProductsOutput struct {
 	Products []*productsv1.Product
+	Facets   *GetProductFacetsOutput
+}
+
+type PurgeDeletedOutput struct {
+	Products   int64
+	Categories int64
 }
@@ -0,0 +1,66 @@
+package models
+
+type RoundingMode string
+
+const (
+	RoundingModeNone    RoundingMode = "none"
+	RoundingModeNearest RoundingMode = "nearest"
+	RoundingModeUp      RoundingMode = "up"
+	RoundingModeDown    RoundingMode = "down"
+)
+
+// RoundingRule rounds converted prices to a multiple of Increment. With an
+// Ending such as 0.99 the rounded whole amount N becomes N - 1 + Ending.
+type RoundingRule struct {
+	Mode      RoundingMode
+	Increment float64
+	Ending    float64
+}
+
+type PriceList struct {
+	ID       int64
+	Code     string
+	Name     string
+	Currency string
+	// BasePriceListID is the list whose prices are converted when this list
+	// has no own price for a product; zero means the product base price.
+	BasePriceListID int64
+	Rounding        RoundingRule
+}
+
+type PriceListOutput struct {
+	PriceList *PriceList
+}
+
+type GetPriceListsOutput struct {
+	PriceLists []*PriceList
+}
+
+type SetPriceListPriceInput struct {
+	PriceListID int64
+	ProductID   int64
+	// Price removes the list's own price when nil.
+	Price *float64
+}
+
+type ExchangeRate struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+}
+
+type ProductPrice struct {
+	ProductID int64
+	Price     float64
+	Currency  string
+	// PriceListCode is empty when the product base price was used.
+	PriceListCode string
+}
+
+type GetProductPriceInput struct {
+	ProductID int64
+	PriceList string
+	Currency  string
+	// Quantity selects the price tier; zero prices a single unit.
+	Quantity int64
+}
@@ -0,0 +1,36 @@
+package models
+
+// PriceTier is the base-currency unit price for quantities from MinQuantity
+// to MaxQuantity inclusive; a zero MaxQuantity leaves the tier open.
+type PriceTier struct {
+	MinQuantity int64
+	MaxQuantity int64
+	UnitPrice   float64
+}
+
+type SetPriceTiersInput struct {
+	ProductID int64
+	// Tiers replace the product's tiers; an empty list removes them.
+	Tiers []*PriceTier
+}
+
+type GetPriceTiersOutput struct {
+	Tiers []*PriceTier
+}
+
+type GetQuantityPriceInput struct {
+	ProductID int64
+	Quantity  int64
+	PriceList string
+	Currency  string
+}
+
+type QuantityPrice struct {
+	ProductID int64
+	Quantity  int64
+	UnitPrice float64
+	LineTotal float64
+	Currency  string
+	// Tier is the tier that set UnitPrice, or nil when no tier applied.
+	Tier *PriceTier
+}
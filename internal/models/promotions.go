@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+type PromotionKind string
+
+const (
+	// PromotionKindPercent takes Value percent off the line.
+	PromotionKindPercent PromotionKind = "percent"
+	// PromotionKindFixed takes Value off every unit.
+	PromotionKindFixed PromotionKind = "fixed"
+	// PromotionKindBuyXGetY makes GetQuantity of every BuyQuantity+GetQuantity units free.
+	PromotionKindBuyXGetY PromotionKind = "buy_x_get_y"
+)
+
+type PromotionStacking string
+
+const (
+	// PromotionStackingExclusive applies only as the first promotion of a line
+	// and stops further promotions.
+	PromotionStackingExclusive PromotionStacking = "exclusive"
+	// PromotionStackingStackable applies on top of the promotions before it.
+	PromotionStackingStackable PromotionStacking = "stackable"
+)
+
+type Promotion struct {
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Kind        PromotionKind `json:"kind"`
+	Value       float64       `json:"value"`
+	BuyQuantity int64         `json:"buy_quantity"`
+	GetQuantity int64         `json:"get_quantity"`
+	// Currency of Value and MinPrice; empty means the base currency.
+	Currency string `json:"currency"`
+	// CategoryIDs and ProductIDs restrict eligible products; empty lists match all.
+	CategoryIDs []int64 `json:"category_ids"`
+	ProductIDs  []int64 `json:"product_ids"`
+	// MinPrice is the unit price a product must reach to be eligible.
+	MinPrice *float64 `json:"min_price"`
+	// StartsAt and EndsAt bound the promotion; zero values leave the window open.
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	// Priority orders promotions; higher priorities apply first.
+	Priority int64             `json:"priority"`
+	Stacking PromotionStacking `json:"stacking"`
+	Active   bool              `json:"active"`
+}
+
+type PromotionOutput struct {
+	Promotion *Promotion `json:"promotion"`
+}
+
+type GetPromotionsOutput struct {
+	Promotions []*Promotion `json:"promotions"`
+}
+
+type QuoteItem struct {
+	ProductID int64 `json:"product_id"`
+	Quantity  int64 `json:"quantity"`
+}
+
+type QuotePricesInput struct {
+	Items []*QuoteItem `json:"items"`
+	// PriceList and Currency select the unit prices, as in GetProductPriceInput.
+	PriceList string `json:"price_list"`
+	Currency  string `json:"currency"`
+	// Region adds the tax breakdown of the line totals when set.
+	Region string `json:"region"`
+}
+
+type AppliedPromotion struct {
+	PromotionID int64   `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Discount    float64 `json:"discount"`
+}
+
+type QuoteLine struct {
+	ProductID int64   `json:"product_id"`
+	Quantity  int64   `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	// Subtotal is UnitPrice * Quantity; Total is Subtotal less Discount.
+	Subtotal   float64             `json:"subtotal"`
+	Discount   float64             `json:"discount"`
+	Total      float64             `json:"total"`
+	Promotions []*AppliedPromotion `json:"promotions"`
+}
+
+type QuotePricesOutput struct {
+	Lines    []*QuoteLine      `json:"lines"`
+	Currency string            `json:"currency"`
+	Total    float64           `json:"total"`
+	Tax      *ComputeTaxOutput `json:"tax"`
+}
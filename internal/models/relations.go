@@ -0,0 +1,57 @@
+package models
+
+import productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+
+// RelationType tells how a related product relates to the product it is
+// shown with. Relations are directional: an accessory of A is not thereby
+// related to A.
+type RelationType string
+
+const (
+	RelationTypeAccessory   RelationType = "accessory"
+	RelationTypeReplacement RelationType = "replacement"
+	RelationTypeUpsell      RelationType = "upsell"
+	RelationTypeCrossSell   RelationType = "cross_sell"
+)
+
+// RelationTypes lists every relation type.
+var RelationTypes = []RelationType{
+	RelationTypeAccessory,
+	RelationTypeReplacement,
+	RelationTypeUpsell,
+	RelationTypeCrossSell,
+}
+
+type ProductRelation struct {
+	ProductID int64        `json:"product_id"`
+	RelatedID int64        `json:"related_id"`
+	Type      RelationType `json:"type"`
+	// Position orders the related products of one type, starting at 1.
+	Position int64 `json:"position"`
+}
+
+type AddProductRelationInput struct {
+	ProductID int64        `json:"product_id"`
+	RelatedID int64        `json:"related_id"`
+	Type      RelationType `json:"type"`
+	// Position inserts the relation at the given place; zero appends it.
+	Position int64 `json:"position"`
+}
+
+// SetProductRelationsInput replaces the related products of one type, in
+// the given order.
+type SetProductRelationsInput struct {
+	ProductID  int64        `json:"product_id"`
+	Type       RelationType `json:"type"`
+	RelatedIDs []int64      `json:"related_ids"`
+}
+
+type GetProductRelationsOutput struct {
+	Relations []*ProductRelation `json:"relations"`
+}
+
+// RelatedProduct is a related product embedded in GetProductOutput.
+type RelatedProduct struct {
+	Type    RelationType
+	Product *productsv1.Product
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationStatusHeld      ReservationStatus = "held"
+	ReservationStatusCommitted ReservationStatus = "committed"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+type ReservationItem struct {
+	ProductID   int64 `json:"product_id"`
+	WarehouseID int64 `json:"warehouse_id"`
+	Quantity    int64 `json:"quantity"`
+}
+
+type ReserveStockInput struct {
+	Items []*ReservationItem
+	// TTL is how long the hold lasts before the reaper releases it.
+	TTL time.Duration
+}
+
+type Reservation struct {
+	ID        string             `json:"id"`
+	Status    ReservationStatus  `json:"status"`
+	Items     []*ReservationItem `json:"items"`
+	ExpiresAt time.Time          `json:"expires_at"`
+}
+
+type ReservationOutput struct {
+	Reservation *Reservation `json:"reservation"`
+}
+
+type ReleaseExpiredReservationsOutput struct {
+	Released int64
+}
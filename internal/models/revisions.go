@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ProductRevision struct {
+	ProductID int64 `json:"product_id"`
+	Revision  int64 `json:"revision"`
+	// Snapshot is the products row as JSON at the time of the revision.
+	Snapshot  json.RawMessage `json:"snapshot"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type GetProductRevisionsOutput struct {
+	Revisions []*ProductRevision `json:"revisions"`
+}
+
+type DiffProductVersionsInput struct {
+	ProductID    int64 `json:"product_id"`
+	FromRevision int64 `json:"from_revision"`
+	ToRevision   int64 `json:"to_revision"`
+}
+
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+type DiffProductVersionsOutput struct {
+	Changes []*FieldChange `json:"changes"`
+}
+
+type RevertProductInput struct {
+	ProductID int64 `json:"product_id"`
+	Revision  int64 `json:"revision"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+type ScheduledPriceStatus string
+
+const (
+	ScheduledPriceStatusPending   ScheduledPriceStatus = "pending"
+	ScheduledPriceStatusActive    ScheduledPriceStatus = "active"
+	ScheduledPriceStatusFinished  ScheduledPriceStatus = "finished"
+	ScheduledPriceStatusCancelled ScheduledPriceStatus = "cancelled"
+)
+
+// ScheduledPrice changes a product's base price, or its price in a price list
+// when PriceListID is set, for the StartsAt..EndsAt window.
+type ScheduledPrice struct {
+	ID          int64
+	ProductID   int64
+	PriceListID int64
+	Price       float64
+	StartsAt    time.Time
+	// EndsAt restores the previous price when non-zero; otherwise the change is permanent.
+	EndsAt    time.Time
+	Status    ScheduledPriceStatus
+	CreatedAt time.Time
+}
+
+type ScheduledPriceOutput struct {
+	ScheduledPrice *ScheduledPrice
+}
+
+type GetScheduledPricesInput struct {
+	ProductID int64
+	// Statuses keeps entries in any of the listed statuses; empty keeps all.
+	Statuses []ScheduledPriceStatus
+}
+
+type GetScheduledPricesOutput struct {
+	ScheduledPrices []*ScheduledPrice
+}
+
+type ApplyScheduledPricesOutput struct {
+	Applied  int64
+	Reverted int64
+	// Skipped is set when another replica holds the scheduler lock.
+	Skipped bool
+}
+
+// PriceHistoryEntry records a price change. A nil OldPrice is the first price;
+// a nil NewPrice means the price was removed from a price list.
+type PriceHistoryEntry struct {
+	ID               int64
+	ProductID        int64
+	PriceListID      int64
+	OldPrice         *float64
+	NewPrice         *float64
+	ScheduledPriceID int64
+	ChangedAt        time.Time
+}
+
+type GetPriceHistoryInput struct {
+	ProductID int64
+	// PriceListID selects a price list's history; zero selects base prices.
+	PriceListID int64
+	From        time.Time
+	To          time.Time
+	Limit       int64
+	Offset      int64
+}
+
+type GetPriceHistoryOutput struct {
+	Entries []*PriceHistoryEntry
+	Total   int64
+}
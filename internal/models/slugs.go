@@ -0,0 +1,14 @@
+package models
+
+// GetProductBySlugInput looks a product up by its current or a former slug.
+type GetProductBySlugInput struct {
+	Slug string
+	GetProductInput
+}
+
+// GetProductCategoryBySlugInput looks a category up by its current or a
+// former slug.
+type GetProductCategoryBySlugInput struct {
+	Slug string
+	GetProductCategoryInput
+}
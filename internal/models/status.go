@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type ProductStatus string
+
+const (
+	ProductStatusDraft        ProductStatus = "draft"
+	ProductStatusInReview     ProductStatus = "in_review"
+	ProductStatusPublished    ProductStatus = "published"
+	ProductStatusDiscontinued ProductStatus = "discontinued"
+	ProductStatusArchived     ProductStatus = "archived"
+)
+
+// ProductStatuses lists every status in workflow order.
+var ProductStatuses = []ProductStatus{
+	ProductStatusDraft,
+	ProductStatusInReview,
+	ProductStatusPublished,
+	ProductStatusDiscontinued,
+	ProductStatusArchived,
+}
+
+// Actor roles that the status workflow grants transitions to.
+const (
+	ActorRoleEditor   = "editor"
+	ActorRoleReviewer = "reviewer"
+	ActorRoleAdmin    = "admin"
+	// ActorRoleSystem is used by background jobs such as the availability scheduler.
+	ActorRoleSystem = "system"
+)
+
+type ProductTransition struct {
+	ID        int64
+	ProductID int64
+	From      ProductStatus
+	To        ProductStatus
+	Actor     string
+	Role      string
+	Reason    string
+	RequestID string
+	CreatedAt time.Time
+}
+
+type TransitionProductStatusInput struct {
+	ProductID int64
+	To        ProductStatus
+	Reason    string
+}
+
+type TransitionProductStatusOutput struct {
+	Transition *ProductTransition
+}
+
+type GetProductTransitionsOutput struct {
+	Transitions []*ProductTransition
+}
@@ -0,0 +1,57 @@
+package models
+
+type Warehouse struct {
+	ID     int64  `json:"id"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+type WarehouseOutput struct {
+	Warehouse *Warehouse `json:"warehouse"`
+}
+
+type GetWarehousesOutput struct {
+	Warehouses []*Warehouse `json:"warehouses"`
+}
+
+type StockReason string
+
+const (
+	StockReasonReceipt    StockReason = "receipt"
+	StockReasonSale       StockReason = "sale"
+	StockReasonReturn     StockReason = "return"
+	StockReasonDamage     StockReason = "damage"
+	StockReasonCorrection StockReason = "correction"
+	// StockReasonReservationCommit is written when a reservation turns into a deduction.
+	StockReasonReservationCommit StockReason = "reservation_commit"
+)
+
+type AdjustStockInput struct {
+	ProductID   int64 `json:"product_id"`
+	WarehouseID int64 `json:"warehouse_id"`
+	// Delta is added to the on-hand quantity; negative values deduct stock.
+	Delta     int64       `json:"delta"`
+	Reason    StockReason `json:"reason"`
+	Reference string      `json:"reference"`
+}
+
+type StockLevel struct {
+	ProductID     int64  `json:"product_id"`
+	WarehouseID   int64  `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	OnHand        int64  `json:"on_hand"`
+	Reserved      int64  `json:"reserved"`
+	Available     int64  `json:"available"`
+}
+
+type AdjustStockOutput struct {
+	Level *StockLevel `json:"level"`
+}
+
+type GetProductAvailabilityOutput struct {
+	Levels    []*StockLevel `json:"levels"`
+	OnHand    int64         `json:"on_hand"`
+	Reserved  int64         `json:"reserved"`
+	Available int64         `json:"available"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+type TaxClass struct {
+	ID   int64
+	Code string
+	Name string
+}
+
+type TaxClassOutput struct {
+	TaxClass *TaxClass
+}
+
+type GetTaxClassesOutput struct {
+	TaxClasses []*TaxClass
+}
+
+type TaxRegion struct {
+	Code string
+	Name string
+	// DisplayGross shows prices with tax in the region; otherwise net.
+	DisplayGross bool
+}
+
+// TaxRate is the percentage of a tax class in a region from EffectiveFrom
+// until the next rate of the same class and region takes effect.
+type TaxRate struct {
+	ID            int64
+	TaxClassID    int64
+	RegionCode    string
+	Rate          float64
+	EffectiveFrom time.Time
+}
+
+type TaxRateOutput struct {
+	TaxRate *TaxRate
+}
+
+type SetTaxClassInput struct {
+	// ID is a product or category ID, depending on the call.
+	ID int64
+	// TaxClassID clears the assignment when zero.
+	TaxClassID int64
+}
+
+// ProductTaxRate is the rate that applies to a product.
+type ProductTaxRate struct {
+	ProductID    int64
+	TaxClassCode string
+	Rate         float64
+}
+
+type TaxableLine struct {
+	ProductID int64
+	// Amount is net or gross according to the PRICES_INCLUDE_TAX setting.
+	Amount float64
+}
+
+type ComputeTaxInput struct {
+	Region string
+	// At selects the rates in effect; zero means now.
+	At    time.Time
+	Lines []*TaxableLine
+}
+
+type TaxAmount struct {
+	ProductID    int64   `json:"product_id"`
+	TaxClassCode string  `json:"tax_class_code"`
+	Rate         float64 `json:"rate"`
+	Net          float64 `json:"net"`
+	Tax          float64 `json:"tax"`
+	Gross        float64 `json:"gross"`
+	// Display is Gross or Net depending on the region.
+	Display float64 `json:"display"`
+}
+
+type ComputeTaxOutput struct {
+	Region       string       `json:"region"`
+	DisplayGross bool         `json:"display_gross"`
+	Lines        []*TaxAmount `json:"lines"`
+	Net          float64      `json:"net"`
+	Tax          float64      `json:"tax"`
+	Gross        float64      `json:"gross"`
+}
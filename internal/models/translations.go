@@ -0,0 +1,27 @@
+package models
+
+// Translation is the content of a product or category in one locale. An
+// empty Description falls back to the next locale like a missing translation.
+type Translation struct {
+	Locale      string `json:"locale"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type SetProductTranslationInput struct {
+	ProductID int64 `json:"product_id"`
+	Translation
+}
+
+type SetCategoryTranslationInput struct {
+	CategoryID int64 `json:"category_id"`
+	Translation
+}
+
+type TranslationOutput struct {
+	Translation *Translation `json:"translation"`
+}
+
+type GetTranslationsOutput struct {
+	Translations []*Translation `json:"translations"`
+}
@@ -0,0 +1,45 @@
+package models
+
+type Variant struct {
+	ID        int64  `json:"id"`
+	ProductID int64  `json:"product_id"`
+	SKU       string `json:"sku"`
+	// Price overrides the product price when set.
+	Price      *float64          `json:"price,omitempty"`
+	Options    map[string]string `json:"options"`
+	Attributes map[string]any    `json:"attributes"`
+}
+
+type SetProductOptionAxesInput struct {
+	ProductID int64 `json:"product_id"`
+	// Axes are option names such as "size" or "color", in display order.
+	Axes []string `json:"axes"`
+}
+
+type GetProductOptionAxesOutput struct {
+	Axes []string `json:"axes"`
+}
+
+type CreateVariantInput struct {
+	ProductID  int64             `json:"product_id"`
+	SKU        string            `json:"sku"`
+	Price      *float64          `json:"price"`
+	Options    map[string]string `json:"options"`
+	Attributes map[string]any    `json:"attributes"`
+}
+
+type UpdateVariantInput struct {
+	ID         int64             `json:"id"`
+	SKU        string            `json:"sku"`
+	Price      *float64          `json:"price"`
+	Options    map[string]string `json:"options"`
+	Attributes map[string]any    `json:"attributes"`
+}
+
+type VariantOutput struct {
+	Variant *Variant `json:"variant"`
+}
+
+type GetProductVariantsOutput struct {
+	Variants []*Variant `json:"variants"`
+}
@@ -4,6 +4,8 @@ package internal
 
 import (
 	"products/internal/models"
+	"products/pkg/barcode"
+	"time"
 
 	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
 	"golang.org/x/net/context"
@@ -11,14 +13,218 @@ import (
 
 // Controller describes methods, implemented by the Postgres package.
 type Postgres interface {
+	CreateCategoryAttribute(ctx context.Context, input *models.AttributeDefinition) (*models.AttributeDefinition, error)
+	UpdateCategoryAttribute(ctx context.Context, input *models.AttributeDefinition) (*models.AttributeDefinition, error)
+	DeleteCategoryAttribute(ctx context.Context, id int64) error
+	GetCategoryAttributes(ctx context.Context, categoryID int64) ([]*models.AttributeDefinition, error)
+	GetProductAttributes(ctx context.Context, productID int64) (map[string]any, error)
+	GetProductHistory(ctx context.Context, input *models.GetProductHistoryInput) ([]*models.AuditRecord, int64, error)
+	GetProductAvailabilityWindow(ctx context.Context, productID int64) (*models.AvailabilityWindow, error)
+	// GetDueAvailabilityTransitions returns the status changes that availability
+	// windows call for at now. Published products whose window has closed are
+	// discontinued, except components of bundles on sale: the window already
+	// keeps them off the storefront, and they are discontinued once their
+	// bundles are. Products that the scheduler discontinued come back whenever
+	// their window is open again, whether it was moved, extended or removed;
+	// products discontinued by hand stay discontinued.
+	GetDueAvailabilityTransitions(ctx context.Context, now time.Time) ([]*models.ProductTransition, error)
+	// AddBarcode assigns a parsed barcode to a product or to one of its variants.
+	AddBarcode(ctx context.Context, input *models.AddBarcodeInput, parsed *barcode.Barcode) (*models.Barcode, error)
+	// ImportBarcodes adds the rows in one transaction. A row rejected for an
+	// unknown product or variant or a barcode already assigned is rolled back
+	// alone and reported; any other failure rolls back the whole import.
+	ImportBarcodes(ctx context.Context, rows []*models.ParsedBarcodeRow) (*models.ImportBarcodesOutput, error)
+	DeleteBarcode(ctx context.Context, id int64) error
+	// GetProductBarcodes returns the barcodes of a product and its variants.
+	GetProductBarcodes(ctx context.Context, productID int64) ([]*models.Barcode, error)
+	// GetBarcode finds a barcode by its GTIN.
+	GetBarcode(ctx context.Context, gtin string) (*models.Barcode, error)
+	CreateBrand(ctx context.Context, input *models.CreateBrandInput) (*models.Brand, error)
+	GetBrand(ctx context.Context, input *models.GetBrandInput) (*models.Brand, error)
+	UpdateBrand(ctx context.Context, input *models.UpdateBrandInput) (*models.Brand, error)
+	// DeleteBrand soft-deletes a brand that no live product references.
+	DeleteBrand(ctx context.Context, id int64) error
+	RestoreBrand(ctx context.Context, id int64) (*models.Brand, error)
+	GetBrands(ctx context.Context, input *models.GetBrandsInput) ([]*models.Brand, error)
+	// GetBrandRollups counts the products of every live brand that match the
+	// filter and reports their price range. Brands without matching products are
+	// left out.
+	GetBrandRollups(ctx context.Context, input *models.GetBrandRollupsInput) ([]*models.BrandRollup, error)
+	GetProductBrandID(ctx context.Context, productID int64) (int64, error)
+	// SetBundle makes a product a bundle of the given components or replaces its
+	// components.
+	SetBundle(ctx context.Context, input *models.Bundle) error
+	// DeleteBundle turns a bundle back into a plain product.
+	DeleteBundle(ctx context.Context, productID int64) error
+	// GetBundle returns the bundle of a product, or nil when the product is not
+	// a bundle.
+	GetBundle(ctx context.Context, productID int64) (*models.Bundle, error)
+	// GetComponentPricedBundleIDs returns the bundles priced from their components.
+	GetComponentPricedBundleIDs(ctx context.Context) ([]int64, error)
+	CreateCustomerGroup(ctx context.Context, input *models.CustomerGroup) (*models.CustomerGroup, error)
+	UpdateCustomerGroup(ctx context.Context, input *models.CustomerGroup) (*models.CustomerGroup, error)
+	GetCustomerGroup(ctx context.Context, code string) (*models.CustomerGroup, error)
+	GetCustomerGroups(ctx context.Context) ([]*models.CustomerGroup, error)
+	// SetProductVisibility replaces the customer groups that see a restricted
+	// product, or lifts the restriction.
+	SetProductVisibility(ctx context.Context, input *models.SetProductVisibilityInput) error
+	// IsProductVisible reports whether the customer group sees the product. Zero
+	// stands for callers outside any group, who see unrestricted products only.
+	IsProductVisible(ctx context.Context, groupID, productID int64) (bool, error)
+	// GetProductFacets counts the filtered products per category, per brand, per
+	// value of the requested attributes and per price bucket. Each facet ignores
+	// its own filter so that the counts show what selecting another value would
+	// return.
+	GetProductFacets(ctx context.Context, input *models.GetProductFacetsInput) (*models.GetProductFacetsOutput, error)
+	SetProductReorderThreshold(ctx context.Context, input *models.SetReorderThresholdInput) error
+	SetCategoryReorderThreshold(ctx context.Context, input *models.SetReorderThresholdInput) error
+	ListLowStockProducts(ctx context.Context, input *models.ListLowStockProductsInput) ([]*models.LowStockItem, int64, error)
+	// EvaluateLowStock records stock levels that dropped below their threshold and
+	// returns only the new ones. Levels back above threshold are cleared so that
+	// they alert again on the next drop. The unique alert key keeps concurrent
+	// evaluators from reporting the same crossing twice.
+	EvaluateLowStock(ctx context.Context) ([]*models.LowStockItem, error)
+	// AddProductMedia appends an image to the end of the product's gallery.
+	AddProductMedia(ctx context.Context, input *models.AddProductMediaInput) (*models.Media, error)
+	GetProductMedia(ctx context.Context, productID int64) ([]*models.Media, error)
+	GetMedia(ctx context.Context, id int64) (*models.Media, error)
+	SetPrimaryMedia(ctx context.Context, id int64) error
+	ReorderProductMedia(ctx context.Context, input *models.ReorderProductMediaInput) error
+	// DeleteProductMedia closes the gap in the gallery order and moves the
+	// primary flag to the first image when the primary image is deleted.
+	DeleteProductMedia(ctx context.Context, id int64) error
+	SetMediaThumbnail(ctx context.Context, id int64, ref string, thumbnail []byte) error
+	GetThumbnail(ctx context.Context, ref string) ([]byte, error)
 	CreateProductCategory(ctx context.Context, input *models.CreateProductCategoryInput) (*productsv1.ProductCategory, error)
-	GetProductCategory(ctx context.Context, id int64) (*productsv1.ProductCategory, error)
+	GetProductCategory(ctx context.Context, input *models.GetProductCategoryInput) (*productsv1.ProductCategory, error)
 	UpdateProductCategory(ctx context.Context, input *models.UpdateProductCategoryInput) (*productsv1.ProductCategory, error)
-	DeleteProductCategory(ctx context.Context, id int64) error
-	GetProductCategories(ctx context.Context) ([]*productsv1.ProductCategory, error)
+	DeleteProductCategory(ctx context.Context, input *models.DeleteProductCategoryInput) (int64, error)
+	RestoreProductCategory(ctx context.Context, id int64) (*productsv1.ProductCategory, error)
+	GetProductCategories(ctx context.Context, input *models.GetProductCategoriesInput) ([]*productsv1.ProductCategory, error)
 	CreateProduct(ctx context.Context, input *models.CreateProductInput) (*productsv1.Product, error)
-	GetProduct(ctx context.Context, id int64) (*productsv1.Product, error)
-	UpdateProduct(ctx context.Context, input *models.UpdateProductInput) (*productsv1.Product, error)
+	GetProduct(ctx context.Context, input *models.GetProductInput) (*productsv1.Product, error)
+	// UpdateProduct updates the product and, when input.Status differs from from,
+	// moves it to that status in the same transaction.
+	UpdateProduct(ctx context.Context, input *models.UpdateProductInput, from models.ProductStatus) (*productsv1.Product, error)
 	DeleteProduct(ctx context.Context, id int64) error
-	GetProducts(ctx context.Context) ([]*productsv1.Product, error)
+	RestoreProduct(ctx context.Context, id int64) (*productsv1.Product, error)
+	// GetProducts returns the filtered products priced in the filter's price
+	// list, the same price that MinPrice and MaxPrice compare against.
+	GetProducts(ctx context.Context, input *models.GetProductsInput) ([]*productsv1.Product, error)
+	// PurgeDeleted permanently removes products and categories soft-deleted before
+	// the given time. Categories still referenced by products are kept.
+	PurgeDeleted(ctx context.Context, before time.Time) (*models.PurgeDeletedOutput, error)
+	CreatePriceList(ctx context.Context, input *models.PriceList) (*models.PriceList, error)
+	UpdatePriceList(ctx context.Context, input *models.PriceList) (*models.PriceList, error)
+	GetPriceList(ctx context.Context, code string) (*models.PriceList, error)
+	GetPriceListByID(ctx context.Context, id int64) (*models.PriceList, error)
+	GetPriceLists(ctx context.Context) ([]*models.PriceList, error)
+	SetPriceListPrice(ctx context.Context, input *models.SetPriceListPriceInput) error
+	// GetPriceListPrice returns the list's own price for a product, or nil.
+	GetPriceListPrice(ctx context.Context, priceListID, productID int64) (*float64, error)
+	// GetProductPrice prices a unit of a product bought in a quantity with the
+	// product_price database function, which listings and price filters use too:
+	// in the price list when priceListID is set, otherwise at its base price
+	// converted to currency.
+	GetProductPrice(ctx context.Context, productID, priceListID, quantity int64, baseCurrency, currency string) (float64, error)
+	SetExchangeRate(ctx context.Context, input *models.ExchangeRate) error
+	// GetExchangeRate returns how many quote units one base unit buys, using the
+	// inverse of the opposite rate when no direct rate is stored.
+	GetExchangeRate(ctx context.Context, base, quote string) (float64, error)
+	SetPriceTiers(ctx context.Context, input *models.SetPriceTiersInput) error
+	GetPriceTiers(ctx context.Context, productID int64) ([]*models.PriceTier, error)
+	// GetPriceTier returns the tier covering the quantity, or nil.
+	GetPriceTier(ctx context.Context, productID, quantity int64) (*models.PriceTier, error)
+	CreatePromotion(ctx context.Context, input *models.Promotion) (*models.Promotion, error)
+	UpdatePromotion(ctx context.Context, input *models.Promotion) (*models.Promotion, error)
+	DeletePromotion(ctx context.Context, id int64) error
+	GetPromotions(ctx context.Context) ([]*models.Promotion, error)
+	// GetActivePromotions returns the promotions running at the given time in
+	// the order they apply.
+	GetActivePromotions(ctx context.Context, at time.Time) ([]*models.Promotion, error)
+	// AddProductRelation relates a product to another one, at the given position
+	// among the relations of that type or after them.
+	AddProductRelation(ctx context.Context, input *models.AddProductRelationInput) (*models.ProductRelation, error)
+	// SetProductRelations replaces the relations of one type, ordered as given.
+	SetProductRelations(ctx context.Context, input *models.SetProductRelationsInput) error
+	DeleteProductRelation(ctx context.Context, relation *models.ProductRelation) error
+	// GetProductRelations returns the relations of a product by type and
+	// position. Relations to deleted products are left out; they come back when
+	// the product is restored.
+	GetProductRelations(ctx context.Context, productID int64) ([]*models.ProductRelation, error)
+	// GetRelatedProducts returns the related products of a product that match
+	// the filter, ordered by type and position.
+	GetRelatedProducts(ctx context.Context, productID int64, filter *models.ProductFilter) ([]*models.RelatedProduct, error)
+	// ReserveStock holds the quantities of all items or none of them. Items are
+	// locked in key order so that concurrent reservations cannot deadlock.
+	ReserveStock(ctx context.Context, input *models.ReserveStockInput) (*models.Reservation, error)
+	// CommitReservation turns a held reservation into a stock deduction. The
+	// status transition is conditional, so a reservation is committed at most once.
+	CommitReservation(ctx context.Context, id string) (*models.Reservation, error)
+	ReleaseReservation(ctx context.Context, id string) (*models.Reservation, error)
+	// ReleaseExpiredReservations expires up to limit held reservations past their
+	// expiry. Rows locked by other replicas are skipped.
+	ReleaseExpiredReservations(ctx context.Context, limit int64) (int64, error)
+	GetProductAsOf(ctx context.Context, input *models.GetProductInput) (*productsv1.Product, error)
+	GetProductRevision(ctx context.Context, productID, revision int64) (*models.ProductRevision, error)
+	GetProductRevisions(ctx context.Context, productID int64) ([]*models.ProductRevision, error)
+	// CreateScheduledPrice rejects entries that overlap a pending or active entry
+	// for the same product and price list. The product row lock serializes
+	// concurrent schedules.
+	CreateScheduledPrice(ctx context.Context, input *models.ScheduledPrice) (*models.ScheduledPrice, error)
+	// CancelScheduledPrice cancels an entry that has not been applied yet.
+	CancelScheduledPrice(ctx context.Context, id int64) (*models.ScheduledPrice, error)
+	GetScheduledPrices(ctx context.Context, input *models.GetScheduledPricesInput) ([]*models.ScheduledPrice, error)
+	// ApplyScheduledPrices restores the prices of entries whose window ended and
+	// applies entries whose window started, in one transaction. The transaction
+	// holds an advisory lock, and every entry moves to its next status in the
+	// same transaction as its price change, so each entry is applied exactly once
+	// across replicas.
+	ApplyScheduledPrices(ctx context.Context, now time.Time) (*models.ApplyScheduledPricesOutput, error)
+	GetPriceHistory(ctx context.Context, input *models.GetPriceHistoryInput) ([]*models.PriceHistoryEntry, int64, error)
+	// GetProductIDBySlug resolves a current or former product slug; redirected
+	// tells that it was a former one.
+	GetProductIDBySlug(ctx context.Context, s string) (id int64, redirected bool, err error)
+	GetProductSlug(ctx context.Context, productID int64) (string, error)
+	// GetCategoryIDBySlug resolves a current or former category slug; redirected
+	// tells that it was a former one.
+	GetCategoryIDBySlug(ctx context.Context, s string) (id int64, redirected bool, err error)
+	GetCategorySlug(ctx context.Context, categoryID int64) (string, error)
+	GetProductStatus(ctx context.Context, productID int64) (models.ProductStatus, error)
+	// TransitionProductStatus moves a product from one status to another and
+	// records the transition. It fails when the product is no longer in from, so
+	// a transition checked against a stale status is never applied.
+	TransitionProductStatus(ctx context.Context, input *models.TransitionProductStatusInput, from models.ProductStatus) (*models.ProductTransition, error)
+	GetProductTransitions(ctx context.Context, productID int64) ([]*models.ProductTransition, error)
+	CreateWarehouse(ctx context.Context, input *models.Warehouse) (*models.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, input *models.Warehouse) (*models.Warehouse, error)
+	GetWarehouses(ctx context.Context) ([]*models.Warehouse, error)
+	// AdjustStock changes the on-hand quantity with a conditional update, so
+	// concurrent adjustments can never take stock below the reserved quantity.
+	AdjustStock(ctx context.Context, input *models.AdjustStockInput) (*models.StockLevel, error)
+	GetProductAvailability(ctx context.Context, productID int64) ([]*models.StockLevel, error)
+	CreateTaxClass(ctx context.Context, input *models.TaxClass) (*models.TaxClass, error)
+	GetTaxClasses(ctx context.Context) ([]*models.TaxClass, error)
+	SetTaxRegion(ctx context.Context, input *models.TaxRegion) error
+	GetTaxRegion(ctx context.Context, code string) (*models.TaxRegion, error)
+	CreateTaxRate(ctx context.Context, input *models.TaxRate) (*models.TaxRate, error)
+	SetProductTaxClass(ctx context.Context, input *models.SetTaxClassInput) error
+	SetCategoryTaxClass(ctx context.Context, input *models.SetTaxClassInput) error
+	// GetProductTaxRates resolves each product's tax class (its own, else its
+	// category's) and the rate of that class in effect in the region at the given
+	// time. Products whose class has no rate there are reported as an error.
+	GetProductTaxRates(ctx context.Context, region string, at time.Time, productIDs []int64) (map[int64]*models.ProductTaxRate, error)
+	SetProductTranslation(ctx context.Context, input *models.SetProductTranslationInput) (*models.Translation, error)
+	DeleteProductTranslation(ctx context.Context, productID int64, locale string) error
+	GetProductTranslations(ctx context.Context, productID int64) ([]*models.Translation, error)
+	SetCategoryTranslation(ctx context.Context, input *models.SetCategoryTranslationInput) (*models.Translation, error)
+	DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error
+	GetCategoryTranslations(ctx context.Context, categoryID int64) ([]*models.Translation, error)
+	SetProductOptionAxes(ctx context.Context, input *models.SetProductOptionAxesInput) error
+	GetProductOptionAxes(ctx context.Context, productID int64) ([]string, error)
+	CreateVariant(ctx context.Context, input *models.CreateVariantInput) (*models.Variant, error)
+	GetVariant(ctx context.Context, id int64) (*models.Variant, error)
+	UpdateVariant(ctx context.Context, input *models.UpdateVariantInput) (*models.Variant, error)
+	DeleteVariant(ctx context.Context, id int64) error
+	GetProductVariants(ctx context.Context, productID int64) ([]*models.Variant, error)
 }
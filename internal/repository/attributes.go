@@ -0,0 +1,123 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+const attributeColumns = `id, category_id, code, name, type, unit, required, min_value, max_value, enum_values`
+
+func (r *Postgres) CreateCategoryAttribute(ctx context.Context, input *models.AttributeDefinition) (*models.AttributeDefinition, error) {
+	query := `INSERT INTO category_attributes (category_id, code, name, type, unit, required, min_value, max_value, enum_values)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING ` + attributeColumns
+	row := r.db.QueryRowContext(ctx, query, input.CategoryID, input.Code, input.Name, string(input.Type), null.NewString(input.Unit, input.Unit != ""),
+		input.Required, input.Min, input.Max, input.EnumValues)
+	attribute, err := scanAttribute(row)
+	if err != nil {
+		return nil, r.attributeError("creating", input.Code, err)
+	}
+
+	return attribute, nil
+}
+
+func (r *Postgres) UpdateCategoryAttribute(ctx context.Context, input *models.AttributeDefinition) (*models.AttributeDefinition, error) {
+	query := `UPDATE category_attributes SET code = $1, name = $2, type = $3, unit = $4, required = $5, min_value = $6, max_value = $7, enum_values = $8
+		WHERE id = $9 RETURNING ` + attributeColumns
+	row := r.db.QueryRowContext(ctx, query, input.Code, input.Name, string(input.Type), null.NewString(input.Unit, input.Unit != ""),
+		input.Required, input.Min, input.Max, input.EnumValues, input.ID)
+	attribute, err := scanAttribute(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("attribute not found")
+		}
+		return nil, r.attributeError("updating", input.Code, err)
+	}
+
+	return attribute, nil
+}
+
+func (r *Postgres) DeleteCategoryAttribute(ctx context.Context, id int64) error {
+	query := `DELETE FROM category_attributes WHERE id = $1`
+	tag, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("Error deleting category attribute: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("attribute not found")
+	}
+	return nil
+}
+
+func (r *Postgres) GetCategoryAttributes(ctx context.Context, categoryID int64) ([]*models.AttributeDefinition, error) {
+	var attributes []*models.AttributeDefinition
+
+	query := `SELECT ` + attributeColumns + ` FROM category_attributes WHERE category_id = $1 ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	if err != nil {
+		r.logger.Errorf("Error fetching category attributes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attribute, err := scanAttribute(rows)
+		if err != nil {
+			r.logger.Errorf("Error scanning category attribute row: %v", err)
+			return nil, err
+		}
+		attributes = append(attributes, attribute)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return attributes, nil
+}
+
+func (r *Postgres) GetProductAttributes(ctx context.Context, productID int64) (map[string]any, error) {
+	var attributes map[string]any
+
+	query := `SELECT attributes FROM products WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query, productID).Scan(&attributes)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error fetching product attributes: %v", err)
+		return nil, err
+	}
+
+	return attributes, nil
+}
+
+func (r *Postgres) attributeError(action, code string, err error) error {
+	if isConstraintViolation(err, uniqueViolationCode, "category_attributes_code_key") {
+		return fmt.Errorf("attribute %q already exists in the category", code)
+	}
+	r.logger.Errorf("Error %s category attribute: %v", action, err)
+	return err
+}
+
+func scanAttribute(row pgx.Row) (*models.AttributeDefinition, error) {
+	var attribute models.AttributeDefinition
+	var attributeType string
+	var unit null.String
+
+	err := row.Scan(&attribute.ID, &attribute.CategoryID, &attribute.Code, &attribute.Name, &attributeType, &unit,
+		&attribute.Required, &attribute.Min, &attribute.Max, &attribute.EnumValues)
+	if err != nil {
+		return nil, err
+	}
+	attribute.Type = models.AttributeType(attributeType)
+	attribute.Unit = unit.String
+
+	return &attribute, nil
+}
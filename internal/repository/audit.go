@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"products/pkg/reqctx"
+)
+
+func (r *Postgres) writeAudit(ctx context.Context, tx pgx.Tx, entityType string, entityID int64, operation models.AuditOperation, before, after []byte) error {
+	actor := reqctx.Actor(ctx)
+	requestID := reqctx.RequestID(ctx)
+
+	query := `INSERT INTO audit_log (entity_type, entity_id, operation, actor, request_id, before, after) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.Exec(ctx, query, entityType, entityID, string(operation), null.NewString(actor, actor != ""), null.NewString(requestID, requestID != ""), before, after)
+	if err != nil {
+		r.logger.Errorf("Error writing audit record: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Postgres) GetProductHistory(ctx context.Context, input *models.GetProductHistoryInput) ([]*models.AuditRecord, int64, error) {
+	var records []*models.AuditRecord
+
+	from := null.NewTime(input.From, !input.From.IsZero())
+	to := null.NewTime(input.To, !input.To.IsZero())
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR created_at < $4)`
+	err := r.db.QueryRowContext(ctx, countQuery, models.AuditEntityProduct, input.ProductID, from, to).Scan(&total)
+	if err != nil {
+		r.logger.Errorf("Error counting product history: %v", err)
+		return nil, 0, err
+	}
+
+	query := `SELECT id, entity_type, entity_id, operation, actor, request_id, before, after, created_at FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR created_at < $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5 OFFSET $6`
+	rows, err := r.db.QueryContext(ctx, query, models.AuditEntityProduct, input.ProductID, from, to, input.Limit, input.Offset)
+	if err != nil {
+		r.logger.Errorf("Error fetching product history: %v", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record models.AuditRecord
+		var operation string
+		var actor, requestID null.String
+		if err = rows.Scan(&record.ID, &record.EntityType, &record.EntityID, &operation, &actor, &requestID, &record.Before, &record.After, &record.CreatedAt); err != nil {
+			r.logger.Errorf("Error scanning audit record row: %v", err)
+			return nil, 0, err
+		}
+		record.Operation = models.AuditOperation(operation)
+		record.Actor = actor.String
+		record.RequestID = requestID.String
+		records = append(records, &record)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"time"
+)
+
+func (r *Postgres) GetProductAvailabilityWindow(ctx context.Context, productID int64) (*models.AvailabilityWindow, error) {
+	var from, until null.Time
+
+	query := `SELECT available_from, available_until FROM products WHERE id = $1`
+	if err := r.db.QueryRowContext(ctx, query, productID).Scan(&from, &until); err != nil {
+		r.logger.Errorf("Error fetching product availability window: %v", err)
+		return nil, err
+	}
+
+	return &models.AvailabilityWindow{From: from.Time, Until: until.Time}, nil
+}
+
+// GetDueAvailabilityTransitions returns the status changes that availability
+// windows call for at now. Published products whose window has closed are
+// discontinued, except components of bundles on sale: the window already
+// keeps them off the storefront, and they are discontinued once their
+// bundles are. Products that the scheduler discontinued come back whenever
+// their window is open again, whether it was moved, extended or removed;
+// products discontinued by hand stay discontinued.
+func (r *Postgres) GetDueAvailabilityTransitions(ctx context.Context, now time.Time) ([]*models.ProductTransition, error) {
+	query := `SELECT p.id, p.status, 'discontinued' FROM products p
+		WHERE p.deleted_at IS NULL AND p.status = 'published' AND p.available_until <= $1
+		  AND NOT EXISTS (SELECT 1 FROM bundle_components c JOIN products b ON b.id = c.bundle_id
+		                  WHERE c.component_id = p.id AND b.deleted_at IS NULL AND b.status NOT IN ('discontinued', 'archived'))
+		UNION ALL
+		SELECT p.id, p.status, 'published' FROM products p
+		WHERE p.deleted_at IS NULL AND p.status = 'discontinued'
+		  AND ` + availableAt("$1") + `
+		  AND (SELECT t.role FROM product_status_transitions t
+		       WHERE t.product_id = p.id ORDER BY t.created_at DESC, t.id DESC LIMIT 1) = $2
+		ORDER BY 1`
+	rows, err := r.db.QueryContext(ctx, query, now, models.ActorRoleSystem)
+	if err != nil {
+		r.logger.Errorf("Error fetching due availability transitions: %v", err)
+		return nil, err
+	}
+
+	transitions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.ProductTransition, error) {
+		var transition models.ProductTransition
+		var from, to string
+		err := row.Scan(&transition.ProductID, &from, &to)
+		transition.From = models.ProductStatus(from)
+		transition.To = models.ProductStatus(to)
+		return &transition, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning availability transition row: %v", err)
+		return nil, err
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"products/pkg/barcode"
+)
+
+const barcodeColumns = `id, code, format, gtin, product_id, COALESCE(variant_id, 0)`
+
+// barcodeRejectedError is a barcode that cannot be added because of its own
+// data, as opposed to a failure of the database.
+type barcodeRejectedError string
+
+func (e barcodeRejectedError) Error() string {
+	return string(e)
+}
+
+// AddBarcode assigns a parsed barcode to a product or to one of its variants.
+func (r *Postgres) AddBarcode(ctx context.Context, input *models.AddBarcodeInput, parsed *barcode.Barcode) (*models.Barcode, error) {
+	var b *models.Barcode
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		b, err = r.addBarcode(ctx, tx, input, parsed)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// ImportBarcodes adds the rows in one transaction. A row rejected for an
+// unknown product or variant or a barcode already assigned is rolled back
+// alone and reported; any other failure rolls back the whole import.
+func (r *Postgres) ImportBarcodes(ctx context.Context, rows []*models.ParsedBarcodeRow) (*models.ImportBarcodesOutput, error) {
+	var output models.ImportBarcodesOutput
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		for _, row := range rows {
+			savepoint, err := tx.Begin(ctx)
+			if err != nil {
+				r.logger.Errorf("Error starting savepoint: %v", err)
+				return err
+			}
+			b, err := r.addBarcode(ctx, savepoint, row.Input, row.Parsed)
+			if err != nil {
+				var rejected barcodeRejectedError
+				if !errors.As(err, &rejected) {
+					return err
+				}
+				if err = savepoint.Rollback(ctx); err != nil {
+					r.logger.Errorf("Error rolling back savepoint: %v", err)
+					return err
+				}
+				output.Errors = append(output.Errors, &models.BarcodeImportError{Row: row.Row, Code: row.Input.Code, Error: rejected.Error()})
+				continue
+			}
+			if err = savepoint.Commit(ctx); err != nil {
+				r.logger.Errorf("Error releasing savepoint: %v", err)
+				return err
+			}
+			output.Barcodes = append(output.Barcodes, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+func (r *Postgres) addBarcode(ctx context.Context, tx pgx.Tx, input *models.AddBarcodeInput, parsed *barcode.Barcode) (*models.Barcode, error) {
+	query := `INSERT INTO barcodes (gtin, code, format, product_id, variant_id)
+		SELECT $1, $2, $3, p.id, $5 FROM products p
+		WHERE p.id = $4 AND p.deleted_at IS NULL
+		  AND ($5::bigint IS NULL OR EXISTS (SELECT 1 FROM product_variants v WHERE v.id = $5 AND v.product_id = p.id))
+		RETURNING ` + barcodeColumns
+	row := tx.QueryRow(ctx, query, parsed.GTIN, parsed.Code, string(parsed.Format), input.ProductID, nullID(input.VariantID))
+	b, err := scanBarcode(row)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			if input.VariantID != 0 {
+				return nil, barcodeRejectedError("product or variant not found")
+			}
+			return nil, barcodeRejectedError("product not found")
+		case isConstraintViolation(err, uniqueViolationCode, "barcodes_gtin_key"):
+			return nil, barcodeRejectedError(fmt.Sprintf("barcode %s is already assigned", parsed.Code))
+		}
+		r.logger.Errorf("Error adding barcode: %v", err)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (r *Postgres) DeleteBarcode(ctx context.Context, id int64) error {
+	tag, err := r.db.ExecContext(ctx, `DELETE FROM barcodes WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Errorf("Error deleting barcode: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("barcode not found")
+	}
+	return nil
+}
+
+// GetProductBarcodes returns the barcodes of a product and its variants.
+func (r *Postgres) GetProductBarcodes(ctx context.Context, productID int64) ([]*models.Barcode, error) {
+	query := `SELECT ` + barcodeColumns + ` FROM barcodes WHERE product_id = $1 ORDER BY variant_id NULLS FIRST, id`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product barcodes: %v", err)
+		return nil, err
+	}
+	barcodes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Barcode, error) {
+		return scanBarcode(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning barcode row: %v", err)
+		return nil, err
+	}
+
+	return barcodes, nil
+}
+
+// GetBarcode finds a barcode by its GTIN.
+func (r *Postgres) GetBarcode(ctx context.Context, gtin string) (*models.Barcode, error) {
+	query := `SELECT ` + barcodeColumns + ` FROM barcodes WHERE gtin = $1`
+	b, err := scanBarcode(r.db.QueryRowContext(ctx, query, gtin))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("barcode not found")
+		}
+		r.logger.Errorf("Error fetching barcode: %v", err)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func scanBarcode(row pgx.Row) (*models.Barcode, error) {
+	var b models.Barcode
+	var format string
+
+	err := row.Scan(&b.ID, &b.Code, &format, &b.GTIN, &b.ProductID, &b.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	b.Format = barcode.Format(format)
+
+	return &b, nil
+}
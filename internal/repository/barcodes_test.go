@@ -0,0 +1,47 @@
+package postgresql
+
+import (
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"products/pkg/barcode"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportBarcodesRollsBackRejectedRowsOnly(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	productID := createTestProduct(t, r, "Kettle", 100)
+	parse := func(code string) *barcode.Barcode {
+		parsed, err := barcode.Parse(code)
+		require.NoError(t, err)
+		return parsed
+	}
+	_, err := r.AddBarcode(ctx, &models.AddBarcodeInput{ProductID: productID, Code: "96385074"}, parse("96385074"))
+	require.NoError(t, err)
+
+	output, err := r.ImportBarcodes(ctx, []*models.ParsedBarcodeRow{
+		{Row: 0, Input: &models.AddBarcodeInput{ProductID: productID, Code: "4006381333931"}, Parsed: parse("4006381333931")},
+		{Row: 1, Input: &models.AddBarcodeInput{ProductID: productID + 1000, Code: "036000291452"}, Parsed: parse("036000291452")},
+		{Row: 2, Input: &models.AddBarcodeInput{ProductID: productID, Code: "96385074"}, Parsed: parse("96385074")},
+		{Row: 3, Input: &models.AddBarcodeInput{ProductID: productID, Code: "5901234123457"}, Parsed: parse("5901234123457")},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, output.Errors, 2)
+	assert.Equal(t, 1, output.Errors[0].Row)
+	assert.Equal(t, "product not found", output.Errors[0].Error)
+	assert.Equal(t, 2, output.Errors[1].Row)
+	assert.Contains(t, output.Errors[1].Error, "already assigned")
+
+	barcodes, err := r.GetProductBarcodes(ctx, productID)
+	require.NoError(t, err)
+	var codes []string
+	for _, b := range barcodes {
+		codes = append(codes, b.Code)
+	}
+	assert.ElementsMatch(t, []string{"96385074", "4006381333931", "5901234123457"}, codes)
+}
@@ -0,0 +1,218 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+func (r *Postgres) CreateBrand(ctx context.Context, input *models.CreateBrandInput) (*models.Brand, error) {
+	var brand models.Brand
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var after []byte
+		query := `INSERT INTO brands (name, description) VALUES ($1, $2) RETURNING id, name, description, to_jsonb(brands)`
+		err := tx.QueryRow(ctx, query, input.Name, input.Description).Scan(&brand.ID, &brand.Name, &brand.Description, &after)
+		if err != nil {
+			return r.brandError("creating", input.Name, err)
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityBrand, brand.ID, models.AuditOperationCreate, nil, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &brand, nil
+}
+
+func (r *Postgres) GetBrand(ctx context.Context, input *models.GetBrandInput) (*models.Brand, error) {
+	var brand models.Brand
+
+	query := `SELECT id, name, description FROM brands WHERE id = $1 AND ($2 OR deleted_at IS NULL)`
+	err := r.db.QueryRowContext(ctx, query, input.ID, input.IncludeDeleted).Scan(&brand.ID, &brand.Name, &brand.Description)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("brand not found")
+		}
+		r.logger.Errorf("Error fetching brand: %v", err)
+		return nil, err
+	}
+
+	return &brand, nil
+}
+
+func (r *Postgres) UpdateBrand(ctx context.Context, input *models.UpdateBrandInput) (*models.Brand, error) {
+	var brand models.Brand
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockBrand(ctx, tx, input.ID)
+		if err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `UPDATE brands b SET name = $1, description = $2 WHERE id = $3 RETURNING id, name, description, to_jsonb(b)`
+		err = tx.QueryRow(ctx, query, input.Name, input.Description, input.ID).Scan(&brand.ID, &brand.Name, &brand.Description, &after)
+		if err != nil {
+			return r.brandError("updating", input.Name, err)
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityBrand, brand.ID, models.AuditOperationUpdate, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &brand, nil
+}
+
+// DeleteBrand soft-deletes a brand that no live product references.
+func (r *Postgres) DeleteBrand(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockBrand(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		var products int64
+		err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM products WHERE brand_id = $1 AND deleted_at IS NULL`, id).Scan(&products)
+		if err != nil {
+			r.logger.Errorf("Error counting brand products: %v", err)
+			return err
+		}
+		if products > 0 {
+			return fmt.Errorf("brand has %d products", products)
+		}
+
+		var after []byte
+		query := `UPDATE brands b SET deleted_at = now() WHERE id = $1 RETURNING to_jsonb(b)`
+		if err = tx.QueryRow(ctx, query, id).Scan(&after); err != nil {
+			r.logger.Errorf("Error deleting brand: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityBrand, id, models.AuditOperationDelete, before, after)
+	})
+}
+
+func (r *Postgres) RestoreBrand(ctx context.Context, id int64) (*models.Brand, error) {
+	var brand models.Brand
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var before, after []byte
+		query := `WITH before AS (
+				SELECT id, to_jsonb(b) AS snapshot FROM brands b WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE
+			)
+			UPDATE brands b SET deleted_at = NULL FROM before x WHERE b.id = x.id
+			RETURNING b.id, b.name, b.description, x.snapshot, to_jsonb(b)`
+		err := tx.QueryRow(ctx, query, id).Scan(&brand.ID, &brand.Name, &brand.Description, &before, &after)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("deleted brand not found")
+			}
+			return r.brandError("restoring", "", err)
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityBrand, brand.ID, models.AuditOperationRestore, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &brand, nil
+}
+
+func (r *Postgres) GetBrands(ctx context.Context, input *models.GetBrandsInput) ([]*models.Brand, error) {
+	query := `SELECT id, name, description FROM brands WHERE $1 OR deleted_at IS NULL ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query, input.IncludeDeleted)
+	if err != nil {
+		r.logger.Errorf("Error fetching brands: %v", err)
+		return nil, err
+	}
+
+	brands, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Brand, error) {
+		var brand models.Brand
+		err := row.Scan(&brand.ID, &brand.Name, &brand.Description)
+		return &brand, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning brand row: %v", err)
+		return nil, err
+	}
+
+	return brands, nil
+}
+
+// GetBrandRollups counts the products of every live brand that match the
+// filter and reports their price range. Brands without matching products are
+// left out.
+func (r *Postgres) GetBrandRollups(ctx context.Context, input *models.GetBrandRollupsInput) ([]*models.BrandRollup, error) {
+	var args queryArgs
+	conditions := productConditions(&input.Filter, noFacet, &args)
+
+	query := `SELECT b.id, b.name, COUNT(*), MIN(p.price), MAX(p.price), ROUND(AVG(p.price), 2)
+		FROM products p
+		JOIN brands b ON b.id = p.brand_id AND b.deleted_at IS NULL
+		WHERE ` + conditions + `
+		GROUP BY b.id, b.name
+		ORDER BY b.name`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching brand rollups: %v", err)
+		return nil, err
+	}
+
+	rollups, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.BrandRollup, error) {
+		var rollup models.BrandRollup
+		err := row.Scan(&rollup.BrandID, &rollup.Name, &rollup.ProductCount, &rollup.MinPrice, &rollup.MaxPrice, &rollup.AvgPrice)
+		return &rollup, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning brand rollup row: %v", err)
+		return nil, err
+	}
+
+	return rollups, nil
+}
+
+func (r *Postgres) GetProductBrandID(ctx context.Context, productID int64) (int64, error) {
+	var brandID int64
+
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(brand_id, 0) FROM products WHERE id = $1`, productID).Scan(&brandID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error fetching product brand: %v", err)
+		return 0, err
+	}
+
+	return brandID, nil
+}
+
+func (r *Postgres) lockBrand(ctx context.Context, tx pgx.Tx, id int64) ([]byte, error) {
+	var snapshot []byte
+
+	query := `SELECT to_jsonb(b) FROM brands b WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&snapshot)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("brand not found")
+		}
+		r.logger.Errorf("Error locking brand: %v", err)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (r *Postgres) brandError(action, name string, err error) error {
+	if isConstraintViolation(err, uniqueViolationCode, "brands_name_key") {
+		if name == "" {
+			return fmt.Errorf("a brand with the same name already exists")
+		}
+		return fmt.Errorf("brand %q already exists", name)
+	}
+	r.logger.Errorf("Error %s brand: %v", action, err)
+	return err
+}
@@ -0,0 +1,169 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"slices"
+)
+
+// SetBundle makes a product a bundle of the given components or replaces its
+// components.
+func (r *Postgres) SetBundle(ctx context.Context, input *models.Bundle) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+
+		var isComponent bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bundle_components WHERE component_id = $1)`, input.ProductID).Scan(&isComponent)
+		if err != nil {
+			r.logger.Errorf("Error checking bundle components: %v", err)
+			return err
+		}
+		if isComponent {
+			return fmt.Errorf("product %d is a component of another bundle", input.ProductID)
+		}
+
+		ids := make([]int64, len(input.Components))
+		for i, component := range input.Components {
+			ids[i] = component.ProductID
+		}
+		// Locking the components keeps them from being deleted or turned
+		// into bundles until the transaction ends.
+		query := `SELECT p.id, EXISTS (SELECT 1 FROM bundles b WHERE b.product_id = p.id) FROM products p
+			WHERE p.id = ANY($1) AND p.deleted_at IS NULL
+			FOR SHARE OF p`
+		rows, err := tx.Query(ctx, query, ids)
+		if err != nil {
+			r.logger.Errorf("Error fetching bundle components: %v", err)
+			return err
+		}
+		type component struct {
+			id     int64
+			bundle bool
+		}
+		components, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (component, error) {
+			var c component
+			err := row.Scan(&c.id, &c.bundle)
+			return c, err
+		})
+		if err != nil {
+			r.logger.Errorf("Error scanning bundle component row: %v", err)
+			return err
+		}
+		for _, id := range ids {
+			i := slices.IndexFunc(components, func(c component) bool { return c.id == id })
+			if i < 0 {
+				return fmt.Errorf("component product %d not found", id)
+			}
+			if components[i].bundle {
+				return fmt.Errorf("component product %d is a bundle", id)
+			}
+		}
+
+		query = `INSERT INTO bundles (product_id, pricing, discount_percent) VALUES ($1, $2, $3)
+			ON CONFLICT (product_id) DO UPDATE SET pricing = EXCLUDED.pricing, discount_percent = EXCLUDED.discount_percent`
+		if _, err = tx.Exec(ctx, query, input.ProductID, string(input.Pricing), input.DiscountPercent); err != nil {
+			r.logger.Errorf("Error setting bundle: %v", err)
+			return err
+		}
+		if _, err = tx.Exec(ctx, `DELETE FROM bundle_components WHERE bundle_id = $1`, input.ProductID); err != nil {
+			r.logger.Errorf("Error deleting bundle components: %v", err)
+			return err
+		}
+		for i, component := range input.Components {
+			query = `INSERT INTO bundle_components (bundle_id, component_id, quantity, position) VALUES ($1, $2, $3, $4)`
+			if _, err = tx.Exec(ctx, query, input.ProductID, component.ProductID, component.Quantity, i); err != nil {
+				r.logger.Errorf("Error creating bundle component: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBundle turns a bundle back into a plain product.
+func (r *Postgres) DeleteBundle(ctx context.Context, productID int64) error {
+	tag, err := r.db.ExecContext(ctx, `DELETE FROM bundles WHERE product_id = $1`, productID)
+	if err != nil {
+		r.logger.Errorf("Error deleting bundle: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("bundle not found")
+	}
+	return nil
+}
+
+// GetBundle returns the bundle of a product, or nil when the product is not
+// a bundle.
+func (r *Postgres) GetBundle(ctx context.Context, productID int64) (*models.Bundle, error) {
+	bundle := models.Bundle{ProductID: productID}
+	var pricing string
+
+	query := `SELECT pricing, discount_percent FROM bundles WHERE product_id = $1`
+	err := r.db.QueryRowContext(ctx, query, productID).Scan(&pricing, &bundle.DiscountPercent)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Errorf("Error fetching bundle: %v", err)
+		return nil, err
+	}
+	bundle.Pricing = models.BundlePricing(pricing)
+
+	query = `SELECT component_id, quantity FROM bundle_components WHERE bundle_id = $1 ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching bundle components: %v", err)
+		return nil, err
+	}
+	bundle.Components, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.BundleComponent, error) {
+		var component models.BundleComponent
+		err := row.Scan(&component.ProductID, &component.Quantity)
+		return &component, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning bundle component row: %v", err)
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// GetComponentPricedBundleIDs returns the bundles priced from their components.
+func (r *Postgres) GetComponentPricedBundleIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT product_id FROM bundles WHERE pricing = $1`, string(models.BundlePricingComponents))
+	if err != nil {
+		r.logger.Errorf("Error fetching bundles: %v", err)
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.Errorf("Error scanning bundle row: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// checkBundleComponent fails when a product is a component of live bundles;
+// with onSale only bundles that are not discontinued or archived count. The
+// product must be locked so that no bundle can take it in meanwhile.
+func (r *Postgres) checkBundleComponent(ctx context.Context, tx pgx.Tx, productID int64, onSale bool) error {
+	query := `SELECT COALESCE(array_agg(p.id ORDER BY p.id), '{}') FROM bundle_components c JOIN products p ON p.id = c.bundle_id
+		WHERE c.component_id = $1 AND p.deleted_at IS NULL AND (NOT $2 OR p.status NOT IN ('discontinued', 'archived'))`
+	var bundles []int64
+	if err := tx.QueryRow(ctx, query, productID, onSale).Scan(&bundles); err != nil {
+		r.logger.Errorf("Error fetching bundles containing product: %v", err)
+		return err
+	}
+	if len(bundles) > 0 {
+		return fmt.Errorf("product is a component of bundles %v", bundles)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteProductCategoryCascadeKeepsBundleComponents(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	category, err := r.CreateProductCategory(ctx, &models.CreateProductCategoryInput{Name: "Cables"})
+	require.NoError(t, err)
+	component, err := r.CreateProduct(ctx, &models.CreateProductInput{Name: "HDMI cable", Price: 5, CategoryID: category.Id})
+	require.NoError(t, err)
+	bundle := createTestProduct(t, r, "TV kit", 500)
+	require.NoError(t, r.SetBundle(ctx, &models.Bundle{
+		ProductID:  bundle,
+		Pricing:    models.BundlePricingFixed,
+		Components: []*models.BundleComponent{{ProductID: component.Id, Quantity: 1}},
+	}))
+
+	_, err = r.DeleteProductCategory(ctx, &models.DeleteProductCategoryInput{ID: category.Id, Policy: models.CategoryDeletePolicyCascade})
+	require.Error(t, err)
+
+	_, err = r.GetProduct(ctx, &models.GetProductInput{ID: component.Id, IncludeUnpublished: true})
+	assert.NoError(t, err, "the component must stay live")
+}
@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+const customerGroupColumns = `id, code, name, COALESCE(price_list_id, 0)`
+
+func (r *Postgres) CreateCustomerGroup(ctx context.Context, input *models.CustomerGroup) (*models.CustomerGroup, error) {
+	query := `INSERT INTO customer_groups (code, name, price_list_id) VALUES ($1, $2, $3) RETURNING ` + customerGroupColumns
+	group, err := scanCustomerGroup(r.db.QueryRowContext(ctx, query, input.Code, input.Name, nullID(input.PriceListID)))
+	if err != nil {
+		return nil, r.customerGroupError("creating", input.Code, err)
+	}
+
+	return group, nil
+}
+
+func (r *Postgres) UpdateCustomerGroup(ctx context.Context, input *models.CustomerGroup) (*models.CustomerGroup, error) {
+	query := `UPDATE customer_groups SET code = $1, name = $2, price_list_id = $3 WHERE id = $4 RETURNING ` + customerGroupColumns
+	group, err := scanCustomerGroup(r.db.QueryRowContext(ctx, query, input.Code, input.Name, nullID(input.PriceListID), input.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("customer group not found")
+		}
+		return nil, r.customerGroupError("updating", input.Code, err)
+	}
+
+	return group, nil
+}
+
+func (r *Postgres) GetCustomerGroup(ctx context.Context, code string) (*models.CustomerGroup, error) {
+	query := `SELECT ` + customerGroupColumns + ` FROM customer_groups WHERE code = $1`
+	group, err := scanCustomerGroup(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("customer group %q not found", code)
+		}
+		r.logger.Errorf("Error fetching customer group: %v", err)
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (r *Postgres) GetCustomerGroups(ctx context.Context) ([]*models.CustomerGroup, error) {
+	query := `SELECT ` + customerGroupColumns + ` FROM customer_groups ORDER BY code`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Errorf("Error fetching customer groups: %v", err)
+		return nil, err
+	}
+
+	groups, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.CustomerGroup, error) {
+		return scanCustomerGroup(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning customer group row: %v", err)
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// SetProductVisibility replaces the customer groups that see a restricted
+// product, or lifts the restriction.
+func (r *Postgres) SetProductVisibility(ctx context.Context, input *models.SetProductVisibilityInput) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `UPDATE products SET restricted = $2 WHERE id = $1`, input.ProductID, input.Restricted)
+		if err != nil {
+			r.logger.Errorf("Error setting product restriction: %v", err)
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("product not found")
+		}
+
+		if _, err = tx.Exec(ctx, `DELETE FROM customer_group_products WHERE product_id = $1`, input.ProductID); err != nil {
+			r.logger.Errorf("Error deleting product customer groups: %v", err)
+			return err
+		}
+		query := `INSERT INTO customer_group_products (group_id, product_id)
+			SELECT DISTINCT g.id, $2 FROM unnest($1::bigint[]) AS g(id)`
+		if _, err = tx.Exec(ctx, query, input.GroupIDs, input.ProductID); err != nil {
+			if isConstraintViolation(err, foreignKeyViolationCode, "customer_group_products_group_id_fkey") {
+				return fmt.Errorf("customer group not found")
+			}
+			r.logger.Errorf("Error creating product customer groups: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// IsProductVisible reports whether the customer group sees the product. Zero
+// stands for callers outside any group, who see unrestricted products only.
+func (r *Postgres) IsProductVisible(ctx context.Context, groupID, productID int64) (bool, error) {
+	var visible bool
+
+	query := `SELECT NOT p.restricted OR EXISTS (SELECT 1 FROM customer_group_products g WHERE g.group_id = $1 AND g.product_id = p.id)
+		FROM products p WHERE p.id = $2`
+	if err := r.db.QueryRowContext(ctx, query, groupID, productID).Scan(&visible); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error checking product visibility: %v", err)
+		return false, err
+	}
+
+	return visible, nil
+}
+
+func (r *Postgres) customerGroupError(action, code string, err error) error {
+	switch {
+	case isConstraintViolation(err, uniqueViolationCode, "customer_groups_code_key"):
+		return fmt.Errorf("customer group %q already exists", code)
+	case isConstraintViolation(err, foreignKeyViolationCode, "customer_groups_price_list_id_fkey"):
+		return fmt.Errorf("price list not found")
+	}
+	r.logger.Errorf("Error %s customer group: %v", action, err)
+	return err
+}
+
+func scanCustomerGroup(row pgx.Row) (*models.CustomerGroup, error) {
+	var group models.CustomerGroup
+	if err := row.Scan(&group.ID, &group.Code, &group.Name, &group.PriceListID); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
@@ -0,0 +1,27 @@
+package postgresql
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+	raiseExceptionCode      = "P0001"
+)
+
+func isConstraintViolation(err error, code, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code && pgErr.ConstraintName == constraint
+}
+
+// raisedError returns the exception a database function raised as a plain
+// error, or nil for any other error.
+func raisedError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == raiseExceptionCode {
+		return errors.New(pgErr.Message)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package postgresql
+
+import (
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+// GetProductFacets counts the filtered products per category, per brand, per
+// value of the requested attributes and per price bucket. Each facet ignores
+// its own filter so that the counts show what selecting another value would
+// return.
+func (r *Postgres) GetProductFacets(ctx context.Context, input *models.GetProductFacetsInput) (*models.GetProductFacetsOutput, error) {
+	var output models.GetProductFacetsOutput
+
+	var args queryArgs
+	query := `SELECT COUNT(*) FROM products p WHERE ` + productConditions(&input.Filter, noFacet, &args)
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&output.Total); err != nil {
+		r.logger.Errorf("Error counting products: %v", err)
+		return nil, err
+	}
+
+	categories, err := r.getCategoryFacet(ctx, &input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	output.Categories = categories
+
+	brands, err := r.getBrandFacet(ctx, &input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	output.Brands = brands
+
+	for _, code := range input.AttributeCodes {
+		values, err := r.getAttributeFacet(ctx, &input.Filter, code)
+		if err != nil {
+			return nil, err
+		}
+		output.Attributes = append(output.Attributes, &models.AttributeFacet{Code: code, Values: values})
+	}
+
+	prices, err := r.getPriceFacet(ctx, &input.Filter, input.PriceBucketSize)
+	if err != nil {
+		return nil, err
+	}
+	output.Prices = prices
+
+	return &output, nil
+}
+
+func (r *Postgres) getCategoryFacet(ctx context.Context, filter *models.ProductFilter) ([]*models.CategoryFacet, error) {
+	var facets []*models.CategoryFacet
+
+	var args queryArgs
+	query := `SELECT COALESCE(p.category_id, 0), COUNT(*) FROM products p
+		WHERE ` + productConditions(filter, categoryFacet, &args) + `
+		GROUP BY 1 ORDER BY 2 DESC, 1`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching category facet: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var facet models.CategoryFacet
+		if err = rows.Scan(&facet.CategoryID, &facet.Count); err != nil {
+			r.logger.Errorf("Error scanning category facet row: %v", err)
+			return nil, err
+		}
+		facets = append(facets, &facet)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (r *Postgres) getBrandFacet(ctx context.Context, filter *models.ProductFilter) ([]*models.BrandFacet, error) {
+	var facets []*models.BrandFacet
+
+	var args queryArgs
+	query := `SELECT p.brand_id, COUNT(*) FROM products p
+		WHERE ` + productConditions(filter, brandFacet, &args) + ` AND p.brand_id IS NOT NULL
+		GROUP BY 1 ORDER BY 2 DESC, 1`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching brand facet: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var facet models.BrandFacet
+		if err = rows.Scan(&facet.BrandID, &facet.Count); err != nil {
+			r.logger.Errorf("Error scanning brand facet row: %v", err)
+			return nil, err
+		}
+		facets = append(facets, &facet)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (r *Postgres) getAttributeFacet(ctx context.Context, filter *models.ProductFilter, code string) ([]*models.FacetValue, error) {
+	var values []*models.FacetValue
+
+	var args queryArgs
+	conditions := productConditions(filter, attributeFacet(code), &args)
+	key := args.add(code)
+	query := `SELECT p.attributes->>` + key + `, COUNT(*) FROM products p
+		WHERE ` + conditions + ` AND p.attributes ? ` + key + `
+		GROUP BY 1 ORDER BY 2 DESC, 1`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching attribute facet: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value models.FacetValue
+		if err = rows.Scan(&value.Value, &value.Count); err != nil {
+			r.logger.Errorf("Error scanning attribute facet row: %v", err)
+			return nil, err
+		}
+		values = append(values, &value)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func (r *Postgres) getPriceFacet(ctx context.Context, filter *models.ProductFilter, bucketSize float64) ([]*models.PriceBucket, error) {
+	var buckets []*models.PriceBucket
+
+	var args queryArgs
+	conditions := productConditions(filter, priceFacet, &args)
+	price := productPrice(filter, &args)
+	size := args.add(bucketSize)
+	query := `SELECT floor(` + price + ` / ` + size + `) * ` + size + ` AS bucket, COUNT(*) FROM products p
+		WHERE ` + conditions + `
+		GROUP BY 1 ORDER BY 1`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching price facet: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket models.PriceBucket
+		if err = rows.Scan(&bucket.From, &bucket.Count); err != nil {
+			r.logger.Errorf("Error scanning price facet row: %v", err)
+			return nil, err
+		}
+		bucket.To = bucket.From + bucketSize
+		buckets = append(buckets, &bucket)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return buckets, nil
+}
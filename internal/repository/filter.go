@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"fmt"
+	"products/internal/models"
+	"sort"
+	"strings"
+)
+
+// Facet names passed to productConditions to drop the facet's own filter.
+const (
+	noFacet       = ""
+	categoryFacet = "category"
+	brandFacet    = "brand"
+	priceFacet    = "price"
+)
+
+// availableAt is the condition that a product aliased as p is inside its
+// availability window at the given time expression.
+func availableAt(at string) string {
+	return fmt.Sprintf("(p.available_from IS NULL OR p.available_from <= %s) AND (p.available_until IS NULL OR p.available_until > %s)", at, at)
+}
+
+func attributeFacet(code string) string {
+	return "attribute:" + code
+}
+
+type queryArgs []any
+
+// add appends a query argument and returns its placeholder.
+func (a *queryArgs) add(value any) string {
+	*a = append(*a, value)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// productConditions builds the WHERE clause for products aliased as p,
+// leaving out the filter of the exclude facet.
+func productConditions(filter *models.ProductFilter, exclude string, args *queryArgs) string {
+	var conditions []string
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "p.deleted_at IS NULL")
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		conditions = append(conditions, fmt.Sprintf("p.status = ANY(%s)", args.add(statuses)))
+	} else {
+		conditions = append(conditions, "p.status = 'published'", availableAt("now()"))
+	}
+	if filter.Query != "" {
+		pattern := args.add("%" + escapeLike(filter.Query) + "%")
+		condition := fmt.Sprintf("p.name ILIKE %s OR p.description ILIKE %s", pattern, pattern)
+		if len(filter.Locales) > 0 {
+			condition += fmt.Sprintf(` OR EXISTS (SELECT 1 FROM product_translations t
+				WHERE t.product_id = p.id AND t.locale = ANY(%s) AND (t.name ILIKE %s OR t.description ILIKE %s))`,
+				args.add(filter.Locales), pattern, pattern)
+		}
+		conditions = append(conditions, "("+condition+")")
+	}
+	if len(filter.CategoryIDs) > 0 && exclude != categoryFacet {
+		conditions = append(conditions, fmt.Sprintf("p.category_id = ANY(%s)", args.add(filter.CategoryIDs)))
+	}
+	if len(filter.BrandIDs) > 0 && exclude != brandFacet {
+		conditions = append(conditions, fmt.Sprintf("p.brand_id = ANY(%s)", args.add(filter.BrandIDs)))
+	}
+	if exclude != priceFacet {
+		if filter.MinPrice != nil {
+			conditions = append(conditions, fmt.Sprintf("%s >= %s", productPrice(filter, args), args.add(*filter.MinPrice)))
+		}
+		if filter.MaxPrice != nil {
+			conditions = append(conditions, fmt.Sprintf("%s <= %s", productPrice(filter, args), args.add(*filter.MaxPrice)))
+		}
+	}
+
+	if !filter.IncludeRestricted {
+		conditions = append(conditions, fmt.Sprintf(
+			"(NOT p.restricted OR EXISTS (SELECT 1 FROM customer_group_products g WHERE g.group_id = %s AND g.product_id = p.id))",
+			args.add(filter.CustomerGroupID)))
+	}
+
+	codes := make([]string, 0, len(filter.Attributes))
+	for code := range filter.Attributes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if exclude == attributeFacet(code) || len(filter.Attributes[code]) == 0 {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("p.attributes->>%s = ANY(%s)", args.add(code), args.add(filter.Attributes[code])))
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// productPrice is the price of products aliased as p in the filter's price
+// list, resolved by the product_price database function.
+func productPrice(filter *models.ProductFilter, args *queryArgs) string {
+	return fmt.Sprintf("product_price(p.id, %s, %s)", args.add(nullID(filter.PriceListID)), args.add(filter.BaseCurrency))
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,141 @@
+package postgresql
+
+import (
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+// lowStockLevelsCTE resolves the reorder threshold of every stock level: the
+// product threshold, else the default of the product's category.
+const lowStockLevelsCTE = `WITH levels AS (
+		SELECT s.product_id, s.warehouse_id, s.on_hand - s.reserved AS available,
+		       COALESCE(pt.threshold, ct.threshold) AS threshold
+		FROM stock_levels s
+		JOIN products p ON p.id = s.product_id AND p.deleted_at IS NULL AND p.status <> 'archived'
+		LEFT JOIN product_reorder_thresholds pt ON pt.product_id = s.product_id
+		LEFT JOIN category_reorder_thresholds ct ON ct.category_id = p.category_id
+		WHERE COALESCE(pt.threshold, ct.threshold) IS NOT NULL
+	)`
+
+func (r *Postgres) SetProductReorderThreshold(ctx context.Context, input *models.SetReorderThresholdInput) error {
+	var err error
+	if input.Threshold == nil {
+		_, err = r.db.ExecContext(ctx, `DELETE FROM product_reorder_thresholds WHERE product_id = $1`, input.ID)
+	} else {
+		query := `INSERT INTO product_reorder_thresholds (product_id, threshold) VALUES ($1, $2)
+			ON CONFLICT (product_id) DO UPDATE SET threshold = EXCLUDED.threshold`
+		_, err = r.db.ExecContext(ctx, query, input.ID, *input.Threshold)
+	}
+	if err != nil {
+		if isConstraintViolation(err, foreignKeyViolationCode, "product_reorder_thresholds_product_id_fkey") {
+			return fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error setting product reorder threshold: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Postgres) SetCategoryReorderThreshold(ctx context.Context, input *models.SetReorderThresholdInput) error {
+	var err error
+	if input.Threshold == nil {
+		_, err = r.db.ExecContext(ctx, `DELETE FROM category_reorder_thresholds WHERE category_id = $1`, input.ID)
+	} else {
+		query := `INSERT INTO category_reorder_thresholds (category_id, threshold) VALUES ($1, $2)
+			ON CONFLICT (category_id) DO UPDATE SET threshold = EXCLUDED.threshold`
+		_, err = r.db.ExecContext(ctx, query, input.ID, *input.Threshold)
+	}
+	if err != nil {
+		if isConstraintViolation(err, foreignKeyViolationCode, "category_reorder_thresholds_category_id_fkey") {
+			return fmt.Errorf("category not found")
+		}
+		r.logger.Errorf("Error setting category reorder threshold: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Postgres) ListLowStockProducts(ctx context.Context, input *models.ListLowStockProductsInput) ([]*models.LowStockItem, int64, error) {
+	var warehouseIDs []int64
+	if len(input.WarehouseIDs) > 0 {
+		warehouseIDs = input.WarehouseIDs
+	}
+
+	var total int64
+	countQuery := lowStockLevelsCTE + `
+		SELECT COUNT(*) FROM levels
+		WHERE available < threshold AND ($1::bigint[] IS NULL OR warehouse_id = ANY($1))`
+	if err := r.db.QueryRowContext(ctx, countQuery, warehouseIDs).Scan(&total); err != nil {
+		r.logger.Errorf("Error counting low stock products: %v", err)
+		return nil, 0, err
+	}
+
+	query := lowStockLevelsCTE + `
+		SELECT product_id, warehouse_id, available, threshold FROM levels
+		WHERE available < threshold AND ($1::bigint[] IS NULL OR warehouse_id = ANY($1))
+		ORDER BY available - threshold, product_id, warehouse_id
+		LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, warehouseIDs, input.Limit, input.Offset)
+	if err != nil {
+		r.logger.Errorf("Error fetching low stock products: %v", err)
+		return nil, 0, err
+	}
+	items, err := pgx.CollectRows(rows, scanLowStockItem)
+	if err != nil {
+		r.logger.Errorf("Error scanning low stock row: %v", err)
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// EvaluateLowStock records stock levels that dropped below their threshold and
+// returns only the new ones. Levels back above threshold are cleared so that
+// they alert again on the next drop. The unique alert key keeps concurrent
+// evaluators from reporting the same crossing twice.
+func (r *Postgres) EvaluateLowStock(ctx context.Context) ([]*models.LowStockItem, error) {
+	var crossed []*models.LowStockItem
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := lowStockLevelsCTE + `
+			DELETE FROM low_stock_alerts a
+			WHERE NOT EXISTS (
+				SELECT 1 FROM levels l
+				WHERE l.product_id = a.product_id AND l.warehouse_id = a.warehouse_id AND l.available < l.threshold
+			)`
+		if _, err := tx.Exec(ctx, query); err != nil {
+			r.logger.Errorf("Error clearing low stock alerts: %v", err)
+			return err
+		}
+
+		query = lowStockLevelsCTE + `
+			INSERT INTO low_stock_alerts (product_id, warehouse_id, available, threshold)
+			SELECT product_id, warehouse_id, available, threshold FROM levels WHERE available < threshold
+			ON CONFLICT (product_id, warehouse_id) DO NOTHING
+			RETURNING product_id, warehouse_id, available, threshold`
+		rows, err := tx.Query(ctx, query)
+		if err != nil {
+			r.logger.Errorf("Error recording low stock alerts: %v", err)
+			return err
+		}
+		crossed, err = pgx.CollectRows(rows, scanLowStockItem)
+		if err != nil {
+			r.logger.Errorf("Error scanning low stock row: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return crossed, nil
+}
+
+func scanLowStockItem(row pgx.CollectableRow) (*models.LowStockItem, error) {
+	var item models.LowStockItem
+	err := row.Scan(&item.ProductID, &item.WarehouseID, &item.Available, &item.Threshold)
+	return &item, err
+}
@@ -0,0 +1,233 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+const mediaColumns = `id, product_id, kind, url, alt_text, position, is_primary, COALESCE(thumbnail_ref, '')`
+
+// AddProductMedia appends an image to the end of the product's gallery.
+func (r *Postgres) AddProductMedia(ctx context.Context, input *models.AddProductMediaInput) (*models.Media, error) {
+	var media *models.Media
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+		if err := r.storeThumbnail(ctx, tx, input.ThumbnailRef, input.Thumbnail); err != nil {
+			return err
+		}
+
+		var position int64
+		query := `SELECT COALESCE(MAX(position), 0) + 1 FROM product_media WHERE product_id = $1`
+		if err := tx.QueryRow(ctx, query, input.ProductID).Scan(&position); err != nil {
+			r.logger.Errorf("Error fetching media position: %v", err)
+			return err
+		}
+
+		primary := input.Primary || position == 1
+		if primary {
+			if _, err := tx.Exec(ctx, `UPDATE product_media SET is_primary = false WHERE product_id = $1 AND is_primary`, input.ProductID); err != nil {
+				r.logger.Errorf("Error clearing primary media: %v", err)
+				return err
+			}
+		}
+
+		var err error
+		query = `INSERT INTO product_media (product_id, kind, url, alt_text, position, is_primary, thumbnail_ref)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + mediaColumns
+		media, err = scanMedia(tx.QueryRow(ctx, query, input.ProductID, string(models.MediaKindImage), input.URL, input.AltText,
+			position, primary, null.NewString(input.ThumbnailRef, input.ThumbnailRef != "")))
+		if err != nil {
+			r.logger.Errorf("Error creating product media: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *Postgres) GetProductMedia(ctx context.Context, productID int64) ([]*models.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM product_media WHERE product_id = $1 ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product media: %v", err)
+		return nil, err
+	}
+
+	media, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Media, error) {
+		return scanMedia(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning product media row: %v", err)
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *Postgres) GetMedia(ctx context.Context, id int64) (*models.Media, error) {
+	media, err := scanMedia(r.db.QueryRowContext(ctx, `SELECT `+mediaColumns+` FROM product_media WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("media not found")
+		}
+		r.logger.Errorf("Error fetching media: %v", err)
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *Postgres) SetPrimaryMedia(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var productID int64
+		err := tx.QueryRow(ctx, `SELECT product_id FROM product_media WHERE id = $1 FOR UPDATE`, id).Scan(&productID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("media not found")
+			}
+			r.logger.Errorf("Error locking media: %v", err)
+			return err
+		}
+
+		query := `UPDATE product_media SET is_primary = false WHERE product_id = $1 AND is_primary AND id <> $2`
+		if _, err = tx.Exec(ctx, query, productID, id); err != nil {
+			r.logger.Errorf("Error clearing primary media: %v", err)
+			return err
+		}
+		if _, err = tx.Exec(ctx, `UPDATE product_media SET is_primary = true WHERE id = $1`, id); err != nil {
+			r.logger.Errorf("Error setting primary media: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *Postgres) ReorderProductMedia(ctx context.Context, input *models.ReorderProductMediaInput) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+
+		var total int64
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM product_media WHERE product_id = $1`, input.ProductID).Scan(&total); err != nil {
+			r.logger.Errorf("Error counting product media: %v", err)
+			return err
+		}
+
+		query := `UPDATE product_media m SET position = o.position
+			FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, position)
+			WHERE m.id = o.id AND m.product_id = $1`
+		tag, err := tx.Exec(ctx, query, input.ProductID, input.MediaIDs)
+		if err != nil {
+			r.logger.Errorf("Error reordering product media: %v", err)
+			return err
+		}
+		if tag.RowsAffected() != total || int64(len(input.MediaIDs)) != total {
+			return fmt.Errorf("media order must list every media of product %d exactly once", input.ProductID)
+		}
+		return nil
+	})
+}
+
+// DeleteProductMedia closes the gap in the gallery order and moves the
+// primary flag to the first image when the primary image is deleted.
+func (r *Postgres) DeleteProductMedia(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var productID, position int64
+		var primary bool
+		query := `DELETE FROM product_media WHERE id = $1 RETURNING product_id, position, is_primary`
+		err := tx.QueryRow(ctx, query, id).Scan(&productID, &position, &primary)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("media not found")
+			}
+			r.logger.Errorf("Error deleting media: %v", err)
+			return err
+		}
+
+		query = `UPDATE product_media SET position = position - 1 WHERE product_id = $1 AND position > $2`
+		if _, err = tx.Exec(ctx, query, productID, position); err != nil {
+			r.logger.Errorf("Error shifting media positions: %v", err)
+			return err
+		}
+		if primary {
+			query = `UPDATE product_media SET is_primary = true WHERE product_id = $1 AND position = 1`
+			if _, err = tx.Exec(ctx, query, productID); err != nil {
+				r.logger.Errorf("Error setting primary media: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Postgres) SetMediaThumbnail(ctx context.Context, id int64, ref string, thumbnail []byte) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := r.storeThumbnail(ctx, tx, ref, thumbnail); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `UPDATE product_media SET thumbnail_ref = $2 WHERE id = $1`, id, ref)
+		if err != nil {
+			r.logger.Errorf("Error setting media thumbnail: %v", err)
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("media not found")
+		}
+		return nil
+	})
+}
+
+func (r *Postgres) GetThumbnail(ctx context.Context, ref string) ([]byte, error) {
+	var data []byte
+
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM media_thumbnails WHERE ref = $1`, ref).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("thumbnail not found")
+		}
+		r.logger.Errorf("Error fetching thumbnail: %v", err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// storeThumbnail saves thumbnail data under its content reference. Equal
+// thumbnails share one row.
+func (r *Postgres) storeThumbnail(ctx context.Context, tx pgx.Tx, ref string, thumbnail []byte) error {
+	if ref == "" {
+		return nil
+	}
+
+	query := `INSERT INTO media_thumbnails (ref, data) VALUES ($1, $2) ON CONFLICT (ref) DO NOTHING`
+	if _, err := tx.Exec(ctx, query, ref, thumbnail); err != nil {
+		r.logger.Errorf("Error storing thumbnail: %v", err)
+		return err
+	}
+	return nil
+}
+
+func scanMedia(row pgx.Row) (*models.Media, error) {
+	var media models.Media
+	var kind string
+	err := row.Scan(&media.ID, &media.ProductID, &kind, &media.URL, &media.AltText, &media.Position, &media.Primary, &media.ThumbnailRef)
+	if err != nil {
+		return nil, err
+	}
+	media.Kind = models.MediaKind(kind)
+	return &media, nil
+}
@@ -2,16 +2,21 @@ package postgresql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
 	"golang.org/x/net/context"
 	"products/internal/models"
 	"products/pkg/logger"
+	"products/pkg/reqctx"
 	"products/pkg/storage/postgres"
+	"time"
 )
 
-//go:generate ifacemaker -f postgres.go -o ../postgres.go -i Postgres -s Postgres -p internal -y "Controller describes methods, implemented by the Postgres package."
+//go:generate ifacemaker -f *.go -o ../postgres.go -i Postgres -s Postgres -p internal -y "Controller describes methods, implemented by the Postgres package."
 type Postgres struct {
 	db     postgres.Postgres
 	logger *logger.ApiLogger
@@ -24,21 +29,35 @@ func NewPostgresRepository(db postgres.Postgres, logger *logger.ApiLogger) *Post
 func (r *Postgres) CreateProductCategory(ctx context.Context, input *models.CreateProductCategoryInput) (*productsv1.ProductCategory, error) {
 	var category productsv1.ProductCategory
 
-	query := `INSERT INTO product_categories (name, description) VALUES ($1, $2) RETURNING id, name, description`
-	err := r.db.QueryRowContext(ctx, query, input.Name, input.Description).Scan(&category.Id, &category.Name, &category.Description)
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		slug, err := r.assignSlug(ctx, tx, categorySlugs, 0, input.Slug, input.Name)
+		if err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `INSERT INTO product_categories (name, description, slug) VALUES ($1, $2, $3) RETURNING id, name, description, to_jsonb(product_categories)`
+		err = tx.QueryRow(ctx, query, input.Name, input.Description, slug).Scan(&category.Id, &category.Name, &category.Description, &after)
+		if err != nil {
+			r.logger.Errorf("Error creating product category: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductCategory, category.Id, models.AuditOperationCreate, nil, after)
+	})
 	if err != nil {
-		r.logger.Errorf("Error creating product category: %v", err)
 		return nil, err
 	}
 
 	return &category, nil
 }
 
-func (r *Postgres) GetProductCategory(ctx context.Context, id int64) (*productsv1.ProductCategory, error) {
+func (r *Postgres) GetProductCategory(ctx context.Context, input *models.GetProductCategoryInput) (*productsv1.ProductCategory, error) {
 	var category productsv1.ProductCategory
 
-	query := `SELECT id, name, description FROM product_categories WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&category.Id, &category.Name, &category.Description)
+	query := `SELECT c.id, ` + localized("category_translations", "category_id", "c", "name", "$3") + `, ` +
+		localized("category_translations", "category_id", "c", "description", "$3") + `
+		FROM product_categories c WHERE c.id = $1 AND ($2 OR c.deleted_at IS NULL)`
+	err := r.db.QueryRowContext(ctx, query, input.ID, input.IncludeDeleted, input.Locales).Scan(&category.Id, &category.Name, &category.Description)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("category not found")
@@ -53,31 +72,232 @@ func (r *Postgres) GetProductCategory(ctx context.Context, id int64) (*productsv
 func (r *Postgres) UpdateProductCategory(ctx context.Context, input *models.UpdateProductCategoryInput) (*productsv1.ProductCategory, error) {
 	var category productsv1.ProductCategory
 
-	query := `UPDATE product_categories SET name = $1, description = $2 WHERE id = $3 RETURNING id, name, description`
-	err := r.db.QueryRowContext(ctx, query, input.Name, input.Description, input.ID).Scan(&category.Id, &category.Name, &category.Description)
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockProductCategory(ctx, tx, input.ID)
+		if err != nil {
+			return err
+		}
+		slug, err := r.updatedSlug(ctx, tx, categorySlugs, input.ID, input.Slug, input.Name)
+		if err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `UPDATE product_categories c SET name = $1, description = $2, slug = $4 WHERE id = $3 RETURNING id, name, description, to_jsonb(c)`
+		err = tx.QueryRow(ctx, query, input.Name, input.Description, input.ID, slug).Scan(&category.Id, &category.Name, &category.Description, &after)
+		if err != nil {
+			r.logger.Errorf("Error updating product category: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductCategory, category.Id, models.AuditOperationUpdate, before, after)
+	})
 	if err != nil {
-		r.logger.Errorf("Error updating product category: %v", err)
 		return nil, err
 	}
 
 	return &category, nil
 }
 
-func (r *Postgres) DeleteProductCategory(ctx context.Context, id int64) error {
-	query := `DELETE FROM product_categories WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *Postgres) DeleteProductCategory(ctx context.Context, input *models.DeleteProductCategoryInput) (int64, error) {
+	var affected int64
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockProductCategory(ctx, tx, input.ID)
+		if err != nil {
+			return err
+		}
+
+		switch input.Policy {
+		case models.CategoryDeletePolicyRestrict:
+			err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM products WHERE category_id = $1 AND deleted_at IS NULL`, input.ID).Scan(&affected)
+			if err != nil {
+				r.logger.Errorf("Error counting category products: %v", err)
+				return err
+			}
+			if affected > 0 {
+				return fmt.Errorf("category has %d products", affected)
+			}
+		case models.CategoryDeletePolicyReassign:
+			var id int64
+			err = tx.QueryRow(ctx, `SELECT id FROM product_categories WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, input.ReassignToID).Scan(&id)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("target category not found")
+				}
+				r.logger.Errorf("Error fetching target product category: %v", err)
+				return err
+			}
+			affected, err = r.updateCategoryProducts(ctx, tx, input.ID, models.AuditOperationUpdate, `category_id = $5`, input.ReassignToID)
+		case models.CategoryDeletePolicyCascade:
+			if err = r.checkCategoryBundleComponents(ctx, tx, input.ID, false); err != nil {
+				return err
+			}
+			affected, err = r.updateCategoryProducts(ctx, tx, input.ID, models.AuditOperationDelete, `deleted_at = now()`)
+		case models.CategoryDeletePolicyArchive:
+			if err = r.archiveCategoryProducts(ctx, tx, input.ID, input.ArchiveFrom); err != nil {
+				return err
+			}
+			affected, err = r.updateCategoryProducts(ctx, tx, input.ID, models.AuditOperationUpdate, `category_id = NULL, status = 'archived'`)
+		case models.CategoryDeletePolicyNullify:
+			affected, err = r.updateCategoryProducts(ctx, tx, input.ID, models.AuditOperationUpdate, `category_id = NULL`)
+		default:
+			return fmt.Errorf("unknown delete policy %q", input.Policy)
+		}
+		if err != nil {
+			r.logger.Errorf("Error applying %s policy to category products: %v", input.Policy, err)
+			return err
+		}
+
+		var after []byte
+		query := `UPDATE product_categories c SET deleted_at = now() WHERE id = $1 RETURNING to_jsonb(c)`
+		if err = tx.QueryRow(ctx, query, input.ID).Scan(&after); err != nil {
+			r.logger.Errorf("Error deleting product category: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductCategory, input.ID, models.AuditOperationDelete, before, after)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// updateCategoryProducts applies set to the live products of a category and
+// writes an audit record for every changed product. Extra args start at $5.
+func (r *Postgres) updateCategoryProducts(ctx context.Context, tx pgx.Tx, categoryID int64, operation models.AuditOperation, set string, args ...any) (int64, error) {
+	query := fmt.Sprintf(`WITH before AS (
+			SELECT p.id, to_jsonb(p) AS snapshot FROM products p WHERE p.category_id = $1 AND p.deleted_at IS NULL FOR UPDATE
+		), changed AS (
+			UPDATE products p SET %s FROM before b WHERE p.id = b.id RETURNING p.id, to_jsonb(p) AS snapshot
+		)
+		INSERT INTO audit_log (entity_type, entity_id, operation, actor, request_id, before, after)
+		SELECT '%s', c.id, $2::text, NULLIF($3::text, ''), NULLIF($4::text, ''), b.snapshot, c.snapshot FROM changed c JOIN before b ON b.id = c.id`,
+		set, models.AuditEntityProduct)
+
+	args = append([]any{categoryID, string(operation), reqctx.Actor(ctx), reqctx.RequestID(ctx)}, args...)
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// archiveCategoryProducts locks the live products of a category and records
+// their transition to archived. It fails when any of them is neither archived
+// nor in one of the from statuses, or is a component of a bundle on sale
+// outside the category.
+func (r *Postgres) archiveCategoryProducts(ctx context.Context, tx pgx.Tx, categoryID int64, from []models.ProductStatus) error {
+	statuses := make([]string, len(from))
+	for i, status := range from {
+		statuses[i] = string(status)
+	}
+
+	query := `SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM (
+			SELECT id, status FROM products WHERE category_id = $1 AND deleted_at IS NULL FOR UPDATE
+		) p
+		WHERE status <> $2 AND status <> ALL($3::text[])`
+	var rejected []int64
+	if err := tx.QueryRow(ctx, query, categoryID, string(models.ProductStatusArchived), statuses).Scan(&rejected); err != nil {
+		r.logger.Errorf("Error checking category product statuses: %v", err)
+		return err
+	}
+	if len(rejected) > 0 {
+		return fmt.Errorf("products %v cannot be archived from their status", rejected)
+	}
+
+	query = `INSERT INTO product_status_transitions (product_id, from_status, to_status, actor, role, reason, request_id)
+		SELECT id, status, $2, NULLIF($3::text, ''), NULLIF($4::text, ''), $5, NULLIF($6::text, '') FROM (
+			SELECT id, status FROM products WHERE category_id = $1 AND deleted_at IS NULL FOR UPDATE
+		) p
+		WHERE status <> $2`
+	_, err := tx.Exec(ctx, query, categoryID, string(models.ProductStatusArchived), reqctx.Actor(ctx), reqctx.Role(ctx),
+		fmt.Sprintf("category %d deleted", categoryID), reqctx.RequestID(ctx))
+	if err != nil {
+		r.logger.Errorf("Error recording product status transitions: %v", err)
+		return err
+	}
+
+	return r.checkCategoryBundleComponents(ctx, tx, categoryID, true)
+}
+
+// checkCategoryBundleComponents locks the live products of a category and
+// fails when any of them is a component of live bundles outside the category;
+// with onSale only bundles that are not discontinued or archived count.
+func (r *Postgres) checkCategoryBundleComponents(ctx context.Context, tx pgx.Tx, categoryID int64, onSale bool) error {
+	_, err := tx.Exec(ctx, `SELECT id FROM products WHERE category_id = $1 AND deleted_at IS NULL FOR UPDATE`, categoryID)
 	if err != nil {
-		r.logger.Errorf("Error deleting product category: %v", err)
+		r.logger.Errorf("Error locking category products: %v", err)
 		return err
 	}
+
+	query := `SELECT COALESCE(array_agg(DISTINCT c.component_id ORDER BY c.component_id), '{}')
+		FROM bundle_components c
+		JOIN products p ON p.id = c.component_id
+		JOIN products b ON b.id = c.bundle_id
+		WHERE p.category_id = $1 AND p.deleted_at IS NULL
+		  AND b.deleted_at IS NULL AND (NOT $2 OR b.status NOT IN ('discontinued', 'archived')) AND b.category_id IS DISTINCT FROM $1`
+	var components []int64
+	if err = tx.QueryRow(ctx, query, categoryID, onSale).Scan(&components); err != nil {
+		r.logger.Errorf("Error fetching bundles containing category products: %v", err)
+		return err
+	}
+	if len(components) > 0 {
+		return fmt.Errorf("products %v are components of bundles outside the category", components)
+	}
 	return nil
 }
 
-func (r *Postgres) GetProductCategories(ctx context.Context) ([]*productsv1.ProductCategory, error) {
+func (r *Postgres) lockProductCategory(ctx context.Context, tx pgx.Tx, id int64) ([]byte, error) {
+	var snapshot []byte
+
+	query := `SELECT to_jsonb(c) FROM product_categories c WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&snapshot)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("category not found")
+		}
+		r.logger.Errorf("Error locking product category: %v", err)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (r *Postgres) RestoreProductCategory(ctx context.Context, id int64) (*productsv1.ProductCategory, error) {
+	var category productsv1.ProductCategory
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var before, after []byte
+		query := `WITH before AS (
+				SELECT id, to_jsonb(c) AS snapshot FROM product_categories c WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE
+			)
+			UPDATE product_categories c SET deleted_at = NULL FROM before b WHERE c.id = b.id
+			RETURNING c.id, c.name, c.description, b.snapshot, to_jsonb(c)`
+		err := tx.QueryRow(ctx, query, id).Scan(&category.Id, &category.Name, &category.Description, &before, &after)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("deleted category not found")
+			}
+			r.logger.Errorf("Error restoring product category: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductCategory, category.Id, models.AuditOperationRestore, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *Postgres) GetProductCategories(ctx context.Context, input *models.GetProductCategoriesInput) ([]*productsv1.ProductCategory, error) {
 	var categories []*productsv1.ProductCategory
 
-	query := `SELECT id, name, description FROM product_categories`
-	rows, err := r.db.QueryContext(ctx, query)
+	query := `SELECT c.id, ` + localized("category_translations", "category_id", "c", "name", "$2") + `, ` +
+		localized("category_translations", "category_id", "c", "description", "$2") + `
+		FROM product_categories c WHERE $1 OR c.deleted_at IS NULL`
+	rows, err := r.db.QueryContext(ctx, query, input.IncludeDeleted, input.Locales)
 	if err != nil {
 		r.logger.Errorf("Error fetching product categories: %v", err)
 		return nil, err
@@ -104,21 +324,36 @@ func (r *Postgres) GetProductCategories(ctx context.Context) ([]*productsv1.Prod
 func (r *Postgres) CreateProduct(ctx context.Context, input *models.CreateProductInput) (*productsv1.Product, error) {
 	var product productsv1.Product
 
-	query := `INSERT INTO products (name, description, price, category_id) VALUES ($1, $2, $3, $4) RETURNING id, name, description, price, category_id`
-	err := r.db.QueryRowContext(ctx, query, input.Name, input.Description, input.Price, input.CategoryID).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId)
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		slug, err := r.assignSlug(ctx, tx, productSlugs, 0, input.Slug, input.Name)
+		if err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `INSERT INTO products (name, description, price, category_id, attributes, brand_id, available_from, available_until, slug) VALUES ($1, $2, $3, $4, COALESCE($5, '{}'::jsonb), $6, $7, $8, $9) RETURNING id, name, description, price, COALESCE(category_id, 0), to_jsonb(products)`
+		err = tx.QueryRow(ctx, query, input.Name, input.Description, input.Price, nullID(input.CategoryID), attributesJSON(input.Attributes), nullID(input.BrandID),
+			nullTime(input.AvailabilityWindow.From), nullTime(input.AvailabilityWindow.Until), slug).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId, &after)
+		if err != nil {
+			r.logger.Errorf("Error creating product: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProduct, product.Id, models.AuditOperationCreate, nil, after)
+	})
 	if err != nil {
-		r.logger.Errorf("Error creating product: %v", err)
 		return nil, err
 	}
 
 	return &product, nil
 }
 
-func (r *Postgres) GetProduct(ctx context.Context, id int64) (*productsv1.Product, error) {
+func (r *Postgres) GetProduct(ctx context.Context, input *models.GetProductInput) (*productsv1.Product, error) {
 	var product productsv1.Product
 
-	query := `SELECT id, name, description, price, category_id FROM products WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId)
+	query := `SELECT p.id, ` + localized("product_translations", "product_id", "p", "name", "$4") + `, ` +
+		localized("product_translations", "product_id", "p", "description", "$4") + `, p.price, COALESCE(p.category_id, 0) FROM products p
+		WHERE p.id = $1 AND ($2 OR p.deleted_at IS NULL) AND ($3 OR (p.status = 'published' AND ` + availableAt("now()") + `))`
+	err := r.db.QueryRowContext(ctx, query, input.ID, input.IncludeDeleted, input.IncludeUnpublished, input.Locales).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("product not found")
@@ -130,13 +365,52 @@ func (r *Postgres) GetProduct(ctx context.Context, id int64) (*productsv1.Produc
 	return &product, nil
 }
 
-func (r *Postgres) UpdateProduct(ctx context.Context, input *models.UpdateProductInput) (*productsv1.Product, error) {
+// UpdateProduct updates the product and, when input.Status differs from from,
+// moves it to that status in the same transaction.
+func (r *Postgres) UpdateProduct(ctx context.Context, input *models.UpdateProductInput, from models.ProductStatus) (*productsv1.Product, error) {
 	var product productsv1.Product
 
-	query := `UPDATE products SET name = $1, description = $2, price = $3, category_id = $4 WHERE id = $5 RETURNING id, name, description, price, category_id`
-	err := r.db.QueryRowContext(ctx, query, input.Name, input.Description, input.Price, input.CategoryID, input.ID).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId)
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockProduct(ctx, tx, input.ID)
+		if err != nil {
+			return err
+		}
+		slug, err := r.updatedSlug(ctx, tx, productSlugs, input.ID, input.Slug, input.Name)
+		if err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `UPDATE products p SET name = $1, description = $2, price = $3, category_id = $4, attributes = COALESCE($6, attributes), slug = $12,
+			brand_id = CASE WHEN $7 THEN $8 ELSE brand_id END,
+			available_from = CASE WHEN $9 THEN $10 ELSE available_from END,
+			available_until = CASE WHEN $9 THEN $11 ELSE available_until END
+			WHERE id = $5 RETURNING id, name, description, price, COALESCE(category_id, 0), to_jsonb(p)`
+		var brandID null.Int
+		if input.BrandID != nil {
+			brandID = nullID(*input.BrandID)
+		}
+		var availableFrom, availableUntil null.Time
+		if input.AvailabilityWindow != nil {
+			availableFrom, availableUntil = nullTime(input.AvailabilityWindow.From), nullTime(input.AvailabilityWindow.Until)
+		}
+		err = tx.QueryRow(ctx, query, input.Name, input.Description, input.Price, nullID(input.CategoryID), input.ID, attributesJSON(input.Attributes), input.BrandID != nil, brandID,
+			input.AvailabilityWindow != nil, availableFrom, availableUntil, slug).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId, &after)
+		if err != nil {
+			r.logger.Errorf("Error updating product: %v", err)
+			return err
+		}
+		if err = r.writeAudit(ctx, tx, models.AuditEntityProduct, product.Id, models.AuditOperationUpdate, before, after); err != nil {
+			return err
+		}
+		if input.Status == "" || input.Status == from {
+			return nil
+		}
+		transition := &models.TransitionProductStatusInput{ProductID: input.ID, To: input.Status, Reason: input.StatusReason}
+		_, err = r.transitionProductStatus(ctx, tx, transition, from)
+		return err
+	})
 	if err != nil {
-		r.logger.Errorf("Error updating product: %v", err)
 		return nil, err
 	}
 
@@ -144,20 +418,82 @@ func (r *Postgres) UpdateProduct(ctx context.Context, input *models.UpdateProduc
 }
 
 func (r *Postgres) DeleteProduct(ctx context.Context, id int64) error {
-	query := `DELETE FROM products WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		before, err := r.lockProduct(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err = r.checkBundleComponent(ctx, tx, id, false); err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `UPDATE products p SET deleted_at = now() WHERE id = $1 RETURNING to_jsonb(p)`
+		if err = tx.QueryRow(ctx, query, id).Scan(&after); err != nil {
+			r.logger.Errorf("Error deleting product: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProduct, id, models.AuditOperationDelete, before, after)
+	})
+}
+
+func (r *Postgres) RestoreProduct(ctx context.Context, id int64) (*productsv1.Product, error) {
+	var product productsv1.Product
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var before, after []byte
+		query := `WITH before AS (
+				SELECT id, to_jsonb(p) AS snapshot FROM products p WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE
+			)
+			UPDATE products p SET deleted_at = NULL FROM before b
+			WHERE p.id = b.id
+			  AND NOT EXISTS (SELECT 1 FROM product_categories c WHERE c.id = p.category_id AND c.deleted_at IS NOT NULL)
+			RETURNING p.id, p.name, p.description, p.price, COALESCE(p.category_id, 0), b.snapshot, to_jsonb(p)`
+		err := tx.QueryRow(ctx, query, id).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId, &before, &after)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("deleted product not found or its category is deleted")
+			}
+			r.logger.Errorf("Error restoring product: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProduct, product.Id, models.AuditOperationRestore, before, after)
+	})
 	if err != nil {
-		r.logger.Errorf("Error deleting product: %v", err)
-		return err
+		return nil, err
 	}
-	return nil
+
+	return &product, nil
+}
+
+func (r *Postgres) lockProduct(ctx context.Context, tx pgx.Tx, id int64) ([]byte, error) {
+	var snapshot []byte
+
+	query := `SELECT to_jsonb(p) FROM products p WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&snapshot)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error locking product: %v", err)
+		return nil, err
+	}
+
+	return snapshot, nil
 }
 
-func (r *Postgres) GetProducts(ctx context.Context) ([]*productsv1.Product, error) {
+// GetProducts returns the filtered products priced in the filter's price
+// list, the same price that MinPrice and MaxPrice compare against.
+func (r *Postgres) GetProducts(ctx context.Context, input *models.GetProductsInput) ([]*productsv1.Product, error) {
 	var products []*productsv1.Product
 
-	query := `SELECT id, name, description, price, category_id FROM products`
-	rows, err := r.db.QueryContext(ctx, query)
+	var args queryArgs
+	locales := args.add(input.Locales)
+	query := `SELECT p.id, ` + localized("product_translations", "product_id", "p", "name", locales) + `, ` +
+		localized("product_translations", "product_id", "p", "description", locales) + `, lp.price::real, COALESCE(p.category_id, 0)
+		FROM products p CROSS JOIN LATERAL ` + productPrice(&input.ProductFilter, &args) + ` AS lp(price)
+		WHERE ` + productConditions(&input.ProductFilter, noFacet, &args) + ` ORDER BY p.id`
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		r.logger.Errorf("Error fetching products: %v", err)
 		return nil, err
@@ -180,3 +516,71 @@ func (r *Postgres) GetProducts(ctx context.Context) ([]*productsv1.Product, erro
 
 	return products, nil
 }
+
+// PurgeDeleted permanently removes products and categories soft-deleted before
+// the given time. Categories still referenced by products are kept.
+func (r *Postgres) PurgeDeleted(ctx context.Context, before time.Time) (*models.PurgeDeletedOutput, error) {
+	var output models.PurgeDeletedOutput
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM products WHERE deleted_at < $1`, before)
+		if err != nil {
+			r.logger.Errorf("Error purging deleted products: %v", err)
+			return err
+		}
+		output.Products = tag.RowsAffected()
+
+		tag, err = tx.Exec(ctx, `DELETE FROM product_categories c
+			WHERE c.deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM products p WHERE p.category_id = c.id)`, before)
+		if err != nil {
+			r.logger.Errorf("Error purging deleted product categories: %v", err)
+			return err
+		}
+		output.Categories = tag.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// nullID maps the zero ID used by the API for "no reference" to NULL.
+func nullID(id int64) null.Int {
+	return null.NewInt(id, id != 0)
+}
+
+func nullTime(t time.Time) null.Time {
+	return null.NewTime(t, !t.IsZero())
+}
+
+// attributesJSON encodes product attributes, mapping nil to NULL so that
+// COALESCE keeps the stored value.
+func attributesJSON(attributes map[string]any) []byte {
+	if attributes == nil {
+		return nil
+	}
+	b, _ := json.Marshal(attributes)
+	return b
+}
+
+// inTx runs fn in a transaction that is committed when fn returns nil.
+func (r *Postgres) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		r.logger.Errorf("Error starting transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		r.logger.Errorf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
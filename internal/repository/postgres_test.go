@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"os"
+	"path/filepath"
+	"products/config"
+	"products/internal/models"
+	"products/pkg/logger"
+	storage "products/pkg/storage/postgres"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepository returns a repository on a fresh schema with every
+// migration applied. The tests need a Postgres database given by
+// TEST_POSTGRES_DSN and are skipped without it.
+func newTestRepository(t *testing.T) *Postgres {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(admin.Close)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	_, err = admin.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	migrations, err := filepath.Glob("../../migration/*.up.sql")
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		sql, err := os.ReadFile(migration)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, string(sql))
+		require.NoError(t, err, migration)
+	}
+
+	log := logger.NewApiLogger(&config.Config{})
+	require.NoError(t, log.InitLogger())
+	return NewPostgresRepository(storage.NewPool(pool), log)
+}
+
+func createTestProduct(t *testing.T, r *Postgres, name string, price float64) int64 {
+	t.Helper()
+
+	product, err := r.CreateProduct(context.Background(), &models.CreateProductInput{Name: name, Price: price})
+	require.NoError(t, err)
+	return product.Id
+}
+
+func createTestWarehouse(t *testing.T, r *Postgres, code string) int64 {
+	t.Helper()
+
+	warehouse, err := r.CreateWarehouse(context.Background(), &models.Warehouse{Code: code, Name: code, Active: true})
+	require.NoError(t, err)
+	return warehouse.ID
+}
+
+func receiveTestStock(t *testing.T, r *Postgres, productID, warehouseID, quantity int64) {
+	t.Helper()
+
+	_, err := r.AdjustStock(context.Background(), &models.AdjustStockInput{
+		ProductID:   productID,
+		WarehouseID: warehouseID,
+		Delta:       quantity,
+		Reason:      models.StockReasonReceipt,
+	})
+	require.NoError(t, err)
+}
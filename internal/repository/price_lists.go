@@ -0,0 +1,244 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+const priceListColumns = `id, code, name, currency, COALESCE(base_price_list_id, 0), rounding_mode, rounding_increment, COALESCE(price_ending, 0)`
+
+func (r *Postgres) CreatePriceList(ctx context.Context, input *models.PriceList) (*models.PriceList, error) {
+	query := `INSERT INTO price_lists (code, name, currency, base_price_list_id, rounding_mode, rounding_increment, price_ending)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + priceListColumns
+	row := r.db.QueryRowContext(ctx, query, input.Code, input.Name, input.Currency, nullID(input.BasePriceListID),
+		string(input.Rounding.Mode), input.Rounding.Increment, null.NewFloat(input.Rounding.Ending, input.Rounding.Ending != 0))
+	priceList, err := scanPriceList(row)
+	if err != nil {
+		return nil, r.priceListError("creating", input.Code, err)
+	}
+
+	return priceList, nil
+}
+
+func (r *Postgres) UpdatePriceList(ctx context.Context, input *models.PriceList) (*models.PriceList, error) {
+	query := `UPDATE price_lists SET code = $1, name = $2, currency = $3, base_price_list_id = $4, rounding_mode = $5, rounding_increment = $6, price_ending = $7
+		WHERE id = $8 RETURNING ` + priceListColumns
+	row := r.db.QueryRowContext(ctx, query, input.Code, input.Name, input.Currency, nullID(input.BasePriceListID),
+		string(input.Rounding.Mode), input.Rounding.Increment, null.NewFloat(input.Rounding.Ending, input.Rounding.Ending != 0), input.ID)
+	priceList, err := scanPriceList(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("price list not found")
+		}
+		return nil, r.priceListError("updating", input.Code, err)
+	}
+
+	return priceList, nil
+}
+
+func (r *Postgres) GetPriceList(ctx context.Context, code string) (*models.PriceList, error) {
+	query := `SELECT ` + priceListColumns + ` FROM price_lists WHERE code = $1`
+	priceList, err := scanPriceList(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("price list %q not found", code)
+		}
+		r.logger.Errorf("Error fetching price list: %v", err)
+		return nil, err
+	}
+
+	return priceList, nil
+}
+
+func (r *Postgres) GetPriceListByID(ctx context.Context, id int64) (*models.PriceList, error) {
+	query := `SELECT ` + priceListColumns + ` FROM price_lists WHERE id = $1`
+	priceList, err := scanPriceList(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("price list not found")
+		}
+		r.logger.Errorf("Error fetching price list: %v", err)
+		return nil, err
+	}
+
+	return priceList, nil
+}
+
+func (r *Postgres) GetPriceLists(ctx context.Context) ([]*models.PriceList, error) {
+	query := `SELECT ` + priceListColumns + ` FROM price_lists ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Errorf("Error fetching price lists: %v", err)
+		return nil, err
+	}
+	priceLists, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.PriceList, error) {
+		return scanPriceList(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning price list row: %v", err)
+		return nil, err
+	}
+
+	return priceLists, nil
+}
+
+func (r *Postgres) SetPriceListPrice(ctx context.Context, input *models.SetPriceListPriceInput) error {
+	var price null.Float
+	if input.Price != nil {
+		price = null.FloatFrom(*input.Price)
+	}
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		_, err := r.setPriceListPrice(ctx, tx, input.PriceListID, input.ProductID, price)
+		return err
+	})
+}
+
+// setPriceListPrice sets the list price of a product, or removes the product
+// from the list when price is NULL, audits the change and returns the price
+// it replaced.
+func (r *Postgres) setPriceListPrice(ctx context.Context, tx pgx.Tx, priceListID, productID int64, price null.Float) (null.Float, error) {
+	var previous null.Float
+	var before []byte
+
+	query := `SELECT price, to_jsonb(lp) FROM price_list_prices lp WHERE price_list_id = $1 AND product_id = $2 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, priceListID, productID).Scan(&previous, &before)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Errorf("Error locking price list price: %v", err)
+		return previous, err
+	}
+
+	var after []byte
+	operation := models.AuditOperationUpdate
+	if price.Valid {
+		if before == nil {
+			operation = models.AuditOperationCreate
+		}
+		query = `INSERT INTO price_list_prices AS lp (price_list_id, product_id, price) VALUES ($1, $2, $3)
+			ON CONFLICT (price_list_id, product_id) DO UPDATE SET price = EXCLUDED.price
+			RETURNING to_jsonb(lp)`
+		err = tx.QueryRow(ctx, query, priceListID, productID, price.Float64).Scan(&after)
+	} else {
+		if before == nil {
+			return previous, nil
+		}
+		operation = models.AuditOperationDelete
+		_, err = tx.Exec(ctx, `DELETE FROM price_list_prices WHERE price_list_id = $1 AND product_id = $2`, priceListID, productID)
+	}
+	if err != nil {
+		switch {
+		case isConstraintViolation(err, foreignKeyViolationCode, "price_list_prices_price_list_id_fkey"):
+			return previous, fmt.Errorf("price list not found")
+		case isConstraintViolation(err, foreignKeyViolationCode, "price_list_prices_product_id_fkey"):
+			return previous, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error setting price list price: %v", err)
+		return previous, err
+	}
+	return previous, r.writeAudit(ctx, tx, models.AuditEntityPriceListPrice, productID, operation, before, after)
+}
+
+// GetPriceListPrice returns the list's own price for a product, or nil.
+func (r *Postgres) GetPriceListPrice(ctx context.Context, priceListID, productID int64) (*float64, error) {
+	var price float64
+
+	query := `SELECT price FROM price_list_prices WHERE price_list_id = $1 AND product_id = $2`
+	err := r.db.QueryRowContext(ctx, query, priceListID, productID).Scan(&price)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Errorf("Error fetching price list price: %v", err)
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+// GetProductPrice prices a unit of a product bought in a quantity with the
+// product_price database function, which listings and price filters use too:
+// in the price list when priceListID is set, otherwise at its base price
+// converted to currency.
+func (r *Postgres) GetProductPrice(ctx context.Context, productID, priceListID, quantity int64, baseCurrency, currency string) (float64, error) {
+	var price float64
+
+	query := `SELECT CASE WHEN $2::BIGINT IS NULL AND $5::CHAR(3) <> $4::CHAR(3)
+			THEN apply_price_rounding(product_price(p.id, NULL, $4, $3) * exchange_rate($4, $5), 'none', 0.01, 0)
+			ELSE product_price(p.id, $2, $4, $3) END
+		FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query, productID, nullID(priceListID), quantity, baseCurrency, currency).Scan(&price)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("product not found")
+		}
+		if raised := raisedError(err); raised != nil {
+			return 0, raised
+		}
+		r.logger.Errorf("Error fetching product price: %v", err)
+		return 0, err
+	}
+
+	return price, nil
+}
+
+func (r *Postgres) SetExchangeRate(ctx context.Context, input *models.ExchangeRate) error {
+	query := `INSERT INTO exchange_rates (base_currency, quote_currency, rate) VALUES ($1, $2, $3)
+		ON CONFLICT (base_currency, quote_currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = now()`
+	_, err := r.db.ExecContext(ctx, query, input.BaseCurrency, input.QuoteCurrency, input.Rate)
+	if err != nil {
+		r.logger.Errorf("Error setting exchange rate: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetExchangeRate returns how many quote units one base unit buys, using the
+// inverse of the opposite rate when no direct rate is stored.
+func (r *Postgres) GetExchangeRate(ctx context.Context, base, quote string) (float64, error) {
+	var rate float64
+
+	query := `SELECT rate FROM (
+			SELECT rate, 0 AS preference FROM exchange_rates WHERE base_currency = $1 AND quote_currency = $2
+			UNION ALL
+			SELECT 1 / rate, 1 FROM exchange_rates WHERE base_currency = $2 AND quote_currency = $1
+		) rates ORDER BY preference LIMIT 1`
+	err := r.db.QueryRowContext(ctx, query, base, quote).Scan(&rate)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("no exchange rate from %s to %s", base, quote)
+		}
+		r.logger.Errorf("Error fetching exchange rate: %v", err)
+		return 0, err
+	}
+
+	return rate, nil
+}
+
+func (r *Postgres) priceListError(action, code string, err error) error {
+	switch {
+	case isConstraintViolation(err, uniqueViolationCode, "price_lists_code_key"):
+		return fmt.Errorf("price list %q already exists", code)
+	case isConstraintViolation(err, foreignKeyViolationCode, "price_lists_base_price_list_id_fkey"):
+		return fmt.Errorf("base price list not found")
+	}
+	r.logger.Errorf("Error %s price list: %v", action, err)
+	return err
+}
+
+func scanPriceList(row pgx.Row) (*models.PriceList, error) {
+	var priceList models.PriceList
+	var mode string
+
+	err := row.Scan(&priceList.ID, &priceList.Code, &priceList.Name, &priceList.Currency, &priceList.BasePriceListID,
+		&mode, &priceList.Rounding.Increment, &priceList.Rounding.Ending)
+	if err != nil {
+		return nil, err
+	}
+	priceList.Rounding.Mode = models.RoundingMode(mode)
+
+	return &priceList, nil
+}
@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+func (r *Postgres) SetPriceTiers(ctx context.Context, input *models.SetPriceTiersInput) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM price_tiers WHERE product_id = $1`, input.ProductID); err != nil {
+			r.logger.Errorf("Error deleting price tiers: %v", err)
+			return err
+		}
+
+		for _, tier := range input.Tiers {
+			query := `INSERT INTO price_tiers (product_id, min_quantity, max_quantity, unit_price) VALUES ($1, $2, $3, $4)`
+			_, err := tx.Exec(ctx, query, input.ProductID, tier.MinQuantity, null.NewInt(tier.MaxQuantity, tier.MaxQuantity != 0), tier.UnitPrice)
+			if err != nil {
+				r.logger.Errorf("Error creating price tier: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Postgres) GetPriceTiers(ctx context.Context, productID int64) ([]*models.PriceTier, error) {
+	query := `SELECT min_quantity, COALESCE(max_quantity, 0), unit_price FROM price_tiers WHERE product_id = $1 ORDER BY min_quantity`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching price tiers: %v", err)
+		return nil, err
+	}
+
+	tiers, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.PriceTier, error) {
+		return scanPriceTier(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning price tier row: %v", err)
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+// GetPriceTier returns the tier covering the quantity, or nil.
+func (r *Postgres) GetPriceTier(ctx context.Context, productID, quantity int64) (*models.PriceTier, error) {
+	query := `SELECT min_quantity, COALESCE(max_quantity, 0), unit_price FROM price_tiers
+		WHERE product_id = $1 AND min_quantity <= $2 AND (max_quantity IS NULL OR max_quantity >= $2)
+		ORDER BY min_quantity DESC
+		LIMIT 1`
+	tier, err := scanPriceTier(r.db.QueryRowContext(ctx, query, productID, quantity))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Errorf("Error fetching price tier: %v", err)
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+func scanPriceTier(row pgx.Row) (*models.PriceTier, error) {
+	var tier models.PriceTier
+	if err := row.Scan(&tier.MinQuantity, &tier.MaxQuantity, &tier.UnitPrice); err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
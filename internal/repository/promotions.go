@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"time"
+)
+
+const promotionColumns = `id, name, kind, value, buy_quantity, get_quantity, COALESCE(currency, ''), category_ids, product_ids,
+	min_price, starts_at, ends_at, priority, stacking, active`
+
+func (r *Postgres) CreatePromotion(ctx context.Context, input *models.Promotion) (*models.Promotion, error) {
+	query := `INSERT INTO promotions (name, kind, value, buy_quantity, get_quantity, currency, category_ids, product_ids, min_price, starts_at, ends_at, priority, stacking, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING ` + promotionColumns
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query, promotionArgs(input)...))
+	if err != nil {
+		r.logger.Errorf("Error creating promotion: %v", err)
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (r *Postgres) UpdatePromotion(ctx context.Context, input *models.Promotion) (*models.Promotion, error) {
+	query := `UPDATE promotions SET name = $1, kind = $2, value = $3, buy_quantity = $4, get_quantity = $5, currency = $6, category_ids = $7,
+		product_ids = $8, min_price = $9, starts_at = $10, ends_at = $11, priority = $12, stacking = $13, active = $14
+		WHERE id = $15 RETURNING ` + promotionColumns
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query, append(promotionArgs(input), input.ID)...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		r.logger.Errorf("Error updating promotion: %v", err)
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (r *Postgres) DeletePromotion(ctx context.Context, id int64) error {
+	tag, err := r.db.ExecContext(ctx, `DELETE FROM promotions WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Errorf("Error deleting promotion: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("promotion not found")
+	}
+	return nil
+}
+
+func (r *Postgres) GetPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions ORDER BY priority DESC, id`
+	return r.queryPromotions(ctx, query)
+}
+
+// GetActivePromotions returns the promotions running at the given time in
+// the order they apply.
+func (r *Postgres) GetActivePromotions(ctx context.Context, at time.Time) ([]*models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions
+		WHERE active AND (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY priority DESC, id`
+	return r.queryPromotions(ctx, query, at)
+}
+
+func (r *Postgres) queryPromotions(ctx context.Context, query string, args ...any) ([]*models.Promotion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching promotions: %v", err)
+		return nil, err
+	}
+
+	promotions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Promotion, error) {
+		return scanPromotion(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning promotion row: %v", err)
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+func promotionArgs(input *models.Promotion) []any {
+	categoryIDs, productIDs := input.CategoryIDs, input.ProductIDs
+	if categoryIDs == nil {
+		categoryIDs = []int64{}
+	}
+	if productIDs == nil {
+		productIDs = []int64{}
+	}
+	return []any{
+		input.Name, string(input.Kind), input.Value, input.BuyQuantity, input.GetQuantity,
+		null.NewString(input.Currency, input.Currency != ""), categoryIDs, productIDs, null.FloatFromPtr(input.MinPrice),
+		null.NewTime(input.StartsAt, !input.StartsAt.IsZero()), null.NewTime(input.EndsAt, !input.EndsAt.IsZero()),
+		input.Priority, string(input.Stacking), input.Active,
+	}
+}
+
+func scanPromotion(row pgx.Row) (*models.Promotion, error) {
+	var promotion models.Promotion
+	var kind, stacking string
+	var minPrice null.Float
+	var startsAt, endsAt null.Time
+	err := row.Scan(&promotion.ID, &promotion.Name, &kind, &promotion.Value, &promotion.BuyQuantity, &promotion.GetQuantity,
+		&promotion.Currency, &promotion.CategoryIDs, &promotion.ProductIDs, &minPrice, &startsAt, &endsAt,
+		&promotion.Priority, &stacking, &promotion.Active)
+	if err != nil {
+		return nil, err
+	}
+	promotion.Kind = models.PromotionKind(kind)
+	promotion.Stacking = models.PromotionStacking(stacking)
+	promotion.MinPrice = minPrice.Ptr()
+	promotion.StartsAt = startsAt.Time
+	promotion.EndsAt = endsAt.Time
+	return &promotion, nil
+}
@@ -0,0 +1,183 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"slices"
+)
+
+// AddProductRelation relates a product to another one, at the given position
+// among the relations of that type or after them.
+func (r *Postgres) AddProductRelation(ctx context.Context, input *models.AddProductRelationInput) (*models.ProductRelation, error) {
+	relation := models.ProductRelation{ProductID: input.ProductID, RelatedID: input.RelatedID, Type: input.Type}
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+		if err := r.lockRelatedProducts(ctx, tx, []int64{input.RelatedID}); err != nil {
+			return err
+		}
+
+		var last int64
+		query := `SELECT COALESCE(MAX(position), 0) FROM product_relations WHERE product_id = $1 AND type = $2`
+		if err := tx.QueryRow(ctx, query, input.ProductID, string(input.Type)).Scan(&last); err != nil {
+			r.logger.Errorf("Error fetching product relation position: %v", err)
+			return err
+		}
+		relation.Position = input.Position
+		if relation.Position == 0 || relation.Position > last {
+			relation.Position = last + 1
+		}
+
+		query = `UPDATE product_relations SET position = position + 1 WHERE product_id = $1 AND type = $2 AND position >= $3`
+		if _, err := tx.Exec(ctx, query, input.ProductID, string(input.Type), relation.Position); err != nil {
+			r.logger.Errorf("Error shifting product relation positions: %v", err)
+			return err
+		}
+		query = `INSERT INTO product_relations (product_id, related_id, type, position) VALUES ($1, $2, $3, $4)`
+		if _, err := tx.Exec(ctx, query, input.ProductID, input.RelatedID, string(input.Type), relation.Position); err != nil {
+			if isConstraintViolation(err, uniqueViolationCode, "product_relations_pkey") {
+				return fmt.Errorf("product %d is already related as %s", input.RelatedID, input.Type)
+			}
+			r.logger.Errorf("Error creating product relation: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &relation, nil
+}
+
+// SetProductRelations replaces the relations of one type, ordered as given.
+func (r *Postgres) SetProductRelations(ctx context.Context, input *models.SetProductRelationsInput) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+		if err := r.lockRelatedProducts(ctx, tx, input.RelatedIDs); err != nil {
+			return err
+		}
+
+		query := `DELETE FROM product_relations WHERE product_id = $1 AND type = $2`
+		if _, err := tx.Exec(ctx, query, input.ProductID, string(input.Type)); err != nil {
+			r.logger.Errorf("Error deleting product relations: %v", err)
+			return err
+		}
+		query = `INSERT INTO product_relations (product_id, related_id, type, position)
+			SELECT $1, o.id, $3, o.position FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, position)`
+		if _, err := tx.Exec(ctx, query, input.ProductID, input.RelatedIDs, string(input.Type)); err != nil {
+			r.logger.Errorf("Error creating product relations: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *Postgres) DeleteProductRelation(ctx context.Context, relation *models.ProductRelation) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var position int64
+		query := `DELETE FROM product_relations WHERE product_id = $1 AND related_id = $2 AND type = $3 RETURNING position`
+		err := tx.QueryRow(ctx, query, relation.ProductID, relation.RelatedID, string(relation.Type)).Scan(&position)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("product relation not found")
+			}
+			r.logger.Errorf("Error deleting product relation: %v", err)
+			return err
+		}
+
+		query = `UPDATE product_relations SET position = position - 1 WHERE product_id = $1 AND type = $2 AND position > $3`
+		if _, err = tx.Exec(ctx, query, relation.ProductID, string(relation.Type), position); err != nil {
+			r.logger.Errorf("Error shifting product relation positions: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetProductRelations returns the relations of a product by type and
+// position. Relations to deleted products are left out; they come back when
+// the product is restored.
+func (r *Postgres) GetProductRelations(ctx context.Context, productID int64) ([]*models.ProductRelation, error) {
+	query := `SELECT rel.product_id, rel.related_id, rel.type, rel.position FROM product_relations rel
+		JOIN products p ON p.id = rel.related_id
+		WHERE rel.product_id = $1 AND p.deleted_at IS NULL
+		ORDER BY rel.type, rel.position`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product relations: %v", err)
+		return nil, err
+	}
+	relations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.ProductRelation, error) {
+		var relation models.ProductRelation
+		var relationType string
+		err := row.Scan(&relation.ProductID, &relation.RelatedID, &relationType, &relation.Position)
+		relation.Type = models.RelationType(relationType)
+		return &relation, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning product relation row: %v", err)
+		return nil, err
+	}
+
+	return relations, nil
+}
+
+// GetRelatedProducts returns the related products of a product that match
+// the filter, ordered by type and position.
+func (r *Postgres) GetRelatedProducts(ctx context.Context, productID int64, filter *models.ProductFilter) ([]*models.RelatedProduct, error) {
+	var args queryArgs
+	locales := args.add(filter.Locales)
+	query := `SELECT rel.type, p.id, ` + localized("product_translations", "product_id", "p", "name", locales) + `, ` +
+		localized("product_translations", "product_id", "p", "description", locales) + `, p.price, COALESCE(p.category_id, 0)
+		FROM product_relations rel JOIN products p ON p.id = rel.related_id
+		WHERE rel.product_id = ` + args.add(productID) + ` AND ` + productConditions(filter, noFacet, &args) + `
+		ORDER BY rel.type, rel.position`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorf("Error fetching related products: %v", err)
+		return nil, err
+	}
+	related, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.RelatedProduct, error) {
+		var product productsv1.Product
+		var relationType string
+		err := row.Scan(&relationType, &product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId)
+		return &models.RelatedProduct{Type: models.RelationType(relationType), Product: &product}, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning related product row: %v", err)
+		return nil, err
+	}
+
+	return related, nil
+}
+
+// lockRelatedProducts fails unless every product exists and is not deleted,
+// and keeps them from being deleted until the transaction ends.
+func (r *Postgres) lockRelatedProducts(ctx context.Context, tx pgx.Tx, ids []int64) error {
+	rows, err := tx.Query(ctx, `SELECT id FROM products WHERE id = ANY($1) AND deleted_at IS NULL FOR SHARE`, ids)
+	if err != nil {
+		r.logger.Errorf("Error locking related products: %v", err)
+		return err
+	}
+	found, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.Errorf("Error scanning related product row: %v", err)
+		return err
+	}
+	for _, id := range ids {
+		if !slices.Contains(found, id) {
+			return fmt.Errorf("related product %d not found", id)
+		}
+	}
+	return nil
+}
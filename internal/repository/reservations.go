@@ -0,0 +1,225 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"sort"
+)
+
+// ReserveStock holds the quantities of all items or none of them. Items are
+// locked in key order so that concurrent reservations cannot deadlock.
+func (r *Postgres) ReserveStock(ctx context.Context, input *models.ReserveStockInput) (*models.Reservation, error) {
+	var reservation models.Reservation
+
+	items := make([]*models.ReservationItem, len(input.Items))
+	copy(items, input.Items)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ProductID != items[j].ProductID {
+			return items[i].ProductID < items[j].ProductID
+		}
+		return items[i].WarehouseID < items[j].WarehouseID
+	})
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var status string
+		query := `INSERT INTO stock_reservations (expires_at) VALUES (now() + make_interval(secs => $1)) RETURNING id::text, status, expires_at`
+		err := tx.QueryRow(ctx, query, input.TTL.Seconds()).Scan(&reservation.ID, &status, &reservation.ExpiresAt)
+		if err != nil {
+			r.logger.Errorf("Error creating stock reservation: %v", err)
+			return err
+		}
+		reservation.Status = models.ReservationStatus(status)
+
+		for _, item := range items {
+			query = `UPDATE stock_levels s SET reserved = s.reserved + $3, updated_at = now()
+				FROM warehouses w, products p
+				WHERE s.product_id = $1 AND s.warehouse_id = $2 AND s.on_hand - s.reserved >= $3
+				  AND w.id = s.warehouse_id AND w.active AND p.id = s.product_id AND p.deleted_at IS NULL`
+			tag, err := tx.Exec(ctx, query, item.ProductID, item.WarehouseID, item.Quantity)
+			if err != nil {
+				r.logger.Errorf("Error reserving stock: %v", err)
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return r.reserveStockError(ctx, tx, item)
+			}
+
+			query = `INSERT INTO stock_reservation_items (reservation_id, product_id, warehouse_id, quantity) VALUES ($1::text::uuid, $2, $3, $4)`
+			if _, err = tx.Exec(ctx, query, reservation.ID, item.ProductID, item.WarehouseID, item.Quantity); err != nil {
+				r.logger.Errorf("Error creating stock reservation item: %v", err)
+				return err
+			}
+		}
+		reservation.Items = items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// reserveStockError explains why an item could not be reserved: its product
+// is deleted, its warehouse is inactive or the stock is insufficient.
+func (r *Postgres) reserveStockError(ctx context.Context, tx pgx.Tx, item *models.ReservationItem) error {
+	var productLive, warehouseActive bool
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL),
+		EXISTS (SELECT 1 FROM warehouses WHERE id = $2 AND active)`
+	if err := tx.QueryRow(ctx, query, item.ProductID, item.WarehouseID).Scan(&productLive, &warehouseActive); err != nil {
+		r.logger.Errorf("Error checking reserved product and warehouse: %v", err)
+		return err
+	}
+	switch {
+	case !productLive:
+		return fmt.Errorf("product %d not found", item.ProductID)
+	case !warehouseActive:
+		return fmt.Errorf("warehouse %d not found or inactive", item.WarehouseID)
+	default:
+		return fmt.Errorf("insufficient stock for product %d in warehouse %d", item.ProductID, item.WarehouseID)
+	}
+}
+
+// CommitReservation turns a held reservation into a stock deduction. The
+// status transition is conditional, so a reservation is committed at most once.
+func (r *Postgres) CommitReservation(ctx context.Context, id string) (*models.Reservation, error) {
+	var reservation *models.Reservation
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		reservation, err = r.finishReservation(ctx, tx, id, models.ReservationStatusCommitted)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (r *Postgres) ReleaseReservation(ctx context.Context, id string) (*models.Reservation, error) {
+	var reservation *models.Reservation
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		reservation, err = r.finishReservation(ctx, tx, id, models.ReservationStatusReleased)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// ReleaseExpiredReservations expires up to limit held reservations past their
+// expiry. Rows locked by other replicas are skipped.
+func (r *Postgres) ReleaseExpiredReservations(ctx context.Context, limit int64) (int64, error) {
+	var released int64
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		query := `SELECT id::text FROM stock_reservations WHERE status = 'held' AND expires_at <= now()
+			ORDER BY expires_at LIMIT $1 FOR UPDATE SKIP LOCKED`
+		rows, err := tx.Query(ctx, query, limit)
+		if err != nil {
+			r.logger.Errorf("Error fetching expired reservations: %v", err)
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			r.logger.Errorf("Error scanning expired reservation row: %v", err)
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err = r.finishReservation(ctx, tx, id, models.ReservationStatusExpired); err != nil {
+				return err
+			}
+			released++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return released, nil
+}
+
+func (r *Postgres) finishReservation(ctx context.Context, tx pgx.Tx, id string, status models.ReservationStatus) (*models.Reservation, error) {
+	var reservation models.Reservation
+
+	var current string
+	query := `UPDATE stock_reservations SET status = $2, updated_at = now()
+		WHERE id = $1::text::uuid AND status = 'held' AND ($2 <> 'committed' OR expires_at > now())
+		RETURNING id::text, status, expires_at`
+	err := tx.QueryRow(ctx, query, id, string(status)).Scan(&reservation.ID, &current, &reservation.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.reservationStateError(ctx, tx, id)
+		}
+		r.logger.Errorf("Error updating stock reservation: %v", err)
+		return nil, err
+	}
+	reservation.Status = models.ReservationStatus(current)
+
+	query = `SELECT product_id, warehouse_id, quantity FROM stock_reservation_items WHERE reservation_id = $1::text::uuid ORDER BY product_id, warehouse_id`
+	rows, err := tx.Query(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("Error fetching stock reservation items: %v", err)
+		return nil, err
+	}
+	reservation.Items, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.ReservationItem, error) {
+		var item models.ReservationItem
+		err := row.Scan(&item.ProductID, &item.WarehouseID, &item.Quantity)
+		return &item, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning stock reservation item row: %v", err)
+		return nil, err
+	}
+
+	for _, item := range reservation.Items {
+		if status == models.ReservationStatusCommitted {
+			query = `UPDATE stock_levels SET on_hand = on_hand - $3, reserved = reserved - $3, updated_at = now() WHERE product_id = $1 AND warehouse_id = $2`
+		} else {
+			query = `UPDATE stock_levels SET reserved = reserved - $3, updated_at = now() WHERE product_id = $1 AND warehouse_id = $2`
+		}
+		if _, err = tx.Exec(ctx, query, item.ProductID, item.WarehouseID, item.Quantity); err != nil {
+			r.logger.Errorf("Error updating reserved stock: %v", err)
+			return nil, err
+		}
+
+		if status == models.ReservationStatusCommitted {
+			err = r.writeStockMovement(ctx, tx, item.ProductID, item.WarehouseID, -item.Quantity, models.StockReasonReservationCommit, reservation.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &reservation, nil
+}
+
+// reservationStateError explains why a reservation could not change status.
+func (r *Postgres) reservationStateError(ctx context.Context, tx pgx.Tx, id string) error {
+	var status string
+	var expired bool
+	query := `SELECT status, expires_at <= now() FROM stock_reservations WHERE id = $1::text::uuid`
+	err := tx.QueryRow(ctx, query, id).Scan(&status, &expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("reservation not found")
+		}
+		r.logger.Errorf("Error fetching stock reservation: %v", err)
+		return err
+	}
+	if status == string(models.ReservationStatusHeld) && expired {
+		return fmt.Errorf("reservation expired")
+	}
+	return fmt.Errorf("reservation is already %s", status)
+}
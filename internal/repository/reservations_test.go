@@ -0,0 +1,176 @@
+package postgresql
+
+import (
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// raceReservations runs reserve concurrently in n goroutines released at
+// once and returns the reservations that succeeded.
+func raceReservations(t *testing.T, n int, reserve func(i int) (*models.Reservation, error)) []*models.Reservation {
+	t.Helper()
+
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		reservations []*models.Reservation
+	)
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			reservation, err := reserve(i)
+			if err != nil {
+				assert.ErrorContains(t, err, "insufficient stock")
+				return
+			}
+			mu.Lock()
+			reservations = append(reservations, reservation)
+			mu.Unlock()
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	return reservations
+}
+
+func TestReserveStockDoesNotOversell(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	productID := createTestProduct(t, r, "Kettle", 100)
+	warehouseID := createTestWarehouse(t, r, "main")
+	const stock = 10
+	receiveTestStock(t, r, productID, warehouseID, stock)
+
+	reservations := raceReservations(t, 50, func(i int) (*models.Reservation, error) {
+		return r.ReserveStock(ctx, &models.ReserveStockInput{
+			Items: []*models.ReservationItem{{ProductID: productID, WarehouseID: warehouseID, Quantity: int64(i%3 + 1)}},
+			TTL:   time.Minute,
+		})
+	})
+
+	var reserved int64
+	for _, reservation := range reservations {
+		reserved += reservation.Items[0].Quantity
+	}
+	assert.LessOrEqual(t, reserved, int64(stock))
+
+	levels, err := r.GetProductAvailability(ctx, productID)
+	require.NoError(t, err)
+	require.Len(t, levels, 1)
+	assert.Equal(t, int64(stock), levels[0].OnHand)
+	assert.Equal(t, reserved, levels[0].Reserved)
+	assert.GreaterOrEqual(t, levels[0].Available, int64(0))
+}
+
+func TestReserveStockMultipleItemsConcurrently(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	first := createTestProduct(t, r, "Cup", 10)
+	second := createTestProduct(t, r, "Saucer", 5)
+	warehouseID := createTestWarehouse(t, r, "main")
+	const stock = 7
+	receiveTestStock(t, r, first, warehouseID, stock)
+	receiveTestStock(t, r, second, warehouseID, stock)
+
+	// Half of the callers list the items in the opposite order; items are
+	// locked in key order, so none of them may fail with a deadlock.
+	reservations := raceReservations(t, 30, func(i int) (*models.Reservation, error) {
+		items := []*models.ReservationItem{
+			{ProductID: first, WarehouseID: warehouseID, Quantity: 1},
+			{ProductID: second, WarehouseID: warehouseID, Quantity: 1},
+		}
+		if i%2 == 1 {
+			items[0], items[1] = items[1], items[0]
+		}
+		return r.ReserveStock(ctx, &models.ReserveStockInput{Items: items, TTL: time.Minute})
+	})
+	assert.Len(t, reservations, stock)
+
+	for _, productID := range []int64{first, second} {
+		levels, err := r.GetProductAvailability(ctx, productID)
+		require.NoError(t, err)
+		require.Len(t, levels, 1)
+		assert.Equal(t, int64(stock), levels[0].Reserved)
+		assert.Equal(t, int64(0), levels[0].Available)
+	}
+}
+
+func TestFinishReservationOnce(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	productID := createTestProduct(t, r, "Teapot", 50)
+	warehouseID := createTestWarehouse(t, r, "main")
+	receiveTestStock(t, r, productID, warehouseID, 5)
+
+	reservation, err := r.ReserveStock(ctx, &models.ReserveStockInput{
+		Items: []*models.ReservationItem{{ProductID: productID, WarehouseID: warehouseID, Quantity: 2}},
+		TTL:   time.Minute,
+	})
+	require.NoError(t, err)
+
+	// A commit racing a release must apply exactly one of them.
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	start := make(chan struct{})
+	for i, finish := range []func(context.Context, string) (*models.Reservation, error){r.CommitReservation, r.ReleaseReservation} {
+		wg.Add(1)
+		go func(i int, finish func(context.Context, string) (*models.Reservation, error)) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = finish(ctx, reservation.ID)
+		}(i, finish)
+	}
+	close(start)
+	wg.Wait()
+
+	committed := errs[0] == nil
+	assert.NotEqual(t, errs[0] == nil, errs[1] == nil, "exactly one of commit and release must succeed: %v", errs)
+
+	levels, err := r.GetProductAvailability(ctx, productID)
+	require.NoError(t, err)
+	require.Len(t, levels, 1)
+	assert.Equal(t, int64(0), levels[0].Reserved)
+	if committed {
+		assert.Equal(t, int64(3), levels[0].OnHand)
+	} else {
+		assert.Equal(t, int64(5), levels[0].OnHand)
+	}
+}
+
+func TestReserveStockRejectsInactiveWarehouseAndDeletedProduct(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	productID := createTestProduct(t, r, "Toaster", 50)
+	warehouseID := createTestWarehouse(t, r, "closed")
+	receiveTestStock(t, r, productID, warehouseID, 5)
+	reserve := func() error {
+		_, err := r.ReserveStock(ctx, &models.ReserveStockInput{
+			Items: []*models.ReservationItem{{ProductID: productID, WarehouseID: warehouseID, Quantity: 1}},
+			TTL:   time.Minute,
+		})
+		return err
+	}
+
+	_, err := r.UpdateWarehouse(ctx, &models.Warehouse{ID: warehouseID, Code: "closed", Name: "closed", Active: false})
+	require.NoError(t, err)
+	assert.ErrorContains(t, reserve(), "inactive")
+
+	_, err = r.UpdateWarehouse(ctx, &models.Warehouse{ID: warehouseID, Code: "closed", Name: "closed", Active: true})
+	require.NoError(t, err)
+	require.NoError(t, r.DeleteProduct(ctx, productID))
+	assert.ErrorContains(t, reserve(), "not found")
+}
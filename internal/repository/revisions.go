@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+func (r *Postgres) GetProductAsOf(ctx context.Context, input *models.GetProductInput) (*productsv1.Product, error) {
+	var product productsv1.Product
+
+	query := `SELECT p.id, p.name, p.description, p.price, COALESCE(p.category_id, 0)
+		FROM (
+			SELECT snapshot FROM product_revisions
+			WHERE product_id = $1 AND created_at <= $2
+			ORDER BY revision DESC
+			LIMIT 1
+		) r, jsonb_populate_record(NULL::products, r.snapshot) p
+		WHERE ($3 OR p.deleted_at IS NULL)
+		  AND ($4 OR (COALESCE(p.status, 'published') = 'published' AND ` + availableAt("$2") + `))`
+	err := r.db.QueryRowContext(ctx, query, input.ID, input.AsOf, input.IncludeDeleted, input.IncludeUnpublished).Scan(&product.Id, &product.Name, &product.Description, &product.Price, &product.CategoryId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error fetching product revision: %v", err)
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (r *Postgres) GetProductRevision(ctx context.Context, productID, revision int64) (*models.ProductRevision, error) {
+	var rev models.ProductRevision
+
+	query := `SELECT product_id, revision, snapshot, created_at FROM product_revisions WHERE product_id = $1 AND revision = $2`
+	err := r.db.QueryRowContext(ctx, query, productID, revision).Scan(&rev.ProductID, &rev.Revision, &rev.Snapshot, &rev.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product revision not found")
+		}
+		r.logger.Errorf("Error fetching product revision: %v", err)
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+func (r *Postgres) GetProductRevisions(ctx context.Context, productID int64) ([]*models.ProductRevision, error) {
+	var revisions []*models.ProductRevision
+
+	query := `SELECT product_id, revision, snapshot, created_at FROM product_revisions WHERE product_id = $1 ORDER BY revision`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product revisions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rev models.ProductRevision
+		if err = rows.Scan(&rev.ProductID, &rev.Revision, &rev.Snapshot, &rev.CreatedAt); err != nil {
+			r.logger.Errorf("Error scanning product revision row: %v", err)
+			return nil, err
+		}
+		revisions = append(revisions, &rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
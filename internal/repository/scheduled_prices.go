@@ -0,0 +1,311 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"strconv"
+	"time"
+)
+
+// scheduledPricesLockKey is the advisory lock that lets one replica at a time
+// apply scheduled prices.
+const scheduledPricesLockKey = 0x70726963 // "pric"
+
+const scheduledPriceColumns = `id, product_id, COALESCE(price_list_id, 0), price, starts_at, ends_at, status, created_at`
+
+// CreateScheduledPrice rejects entries that overlap a pending or active entry
+// for the same product and price list. The product row lock serializes
+// concurrent schedules.
+func (r *Postgres) CreateScheduledPrice(ctx context.Context, input *models.ScheduledPrice) (*models.ScheduledPrice, error) {
+	var scheduledPrice *models.ScheduledPrice
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+
+		endsAt := null.NewTime(input.EndsAt, !input.EndsAt.IsZero())
+		var overlaps bool
+		query := `SELECT EXISTS (
+				SELECT 1 FROM scheduled_prices
+				WHERE product_id = $1 AND price_list_id IS NOT DISTINCT FROM $2
+				  AND status IN ('pending', 'active')
+				  AND tstzrange(starts_at, ends_at) && tstzrange($3, $4)
+			)`
+		err := tx.QueryRow(ctx, query, input.ProductID, nullID(input.PriceListID), input.StartsAt, endsAt).Scan(&overlaps)
+		if err != nil {
+			r.logger.Errorf("Error checking scheduled price overlap: %v", err)
+			return err
+		}
+		if overlaps {
+			return fmt.Errorf("scheduled price overlaps another scheduled price of product %d", input.ProductID)
+		}
+
+		query = `INSERT INTO scheduled_prices (product_id, price_list_id, price, starts_at, ends_at)
+			VALUES ($1, $2, $3, $4, $5) RETURNING ` + scheduledPriceColumns
+		scheduledPrice, err = scanScheduledPrice(tx.QueryRow(ctx, query, input.ProductID, nullID(input.PriceListID), input.Price, input.StartsAt, endsAt))
+		if err != nil {
+			if isConstraintViolation(err, foreignKeyViolationCode, "scheduled_prices_price_list_id_fkey") {
+				return fmt.Errorf("price list %d not found", input.PriceListID)
+			}
+			r.logger.Errorf("Error creating scheduled price: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledPrice, nil
+}
+
+// CancelScheduledPrice cancels an entry that has not been applied yet.
+func (r *Postgres) CancelScheduledPrice(ctx context.Context, id int64) (*models.ScheduledPrice, error) {
+	query := `UPDATE scheduled_prices SET status = 'cancelled', finished_at = now()
+		WHERE id = $1 AND status = 'pending' RETURNING ` + scheduledPriceColumns
+	scheduledPrice, err := scanScheduledPrice(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("pending scheduled price not found")
+		}
+		r.logger.Errorf("Error cancelling scheduled price: %v", err)
+		return nil, err
+	}
+
+	return scheduledPrice, nil
+}
+
+func (r *Postgres) GetScheduledPrices(ctx context.Context, input *models.GetScheduledPricesInput) ([]*models.ScheduledPrice, error) {
+	statuses := make([]string, len(input.Statuses))
+	for i, status := range input.Statuses {
+		statuses[i] = string(status)
+	}
+
+	query := `SELECT ` + scheduledPriceColumns + ` FROM scheduled_prices
+		WHERE product_id = $1 AND (cardinality($2::text[]) = 0 OR status = ANY($2))
+		ORDER BY starts_at, id`
+	rows, err := r.db.QueryContext(ctx, query, input.ProductID, statuses)
+	if err != nil {
+		r.logger.Errorf("Error fetching scheduled prices: %v", err)
+		return nil, err
+	}
+
+	scheduledPrices, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.ScheduledPrice, error) {
+		return scanScheduledPrice(row)
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning scheduled price row: %v", err)
+		return nil, err
+	}
+
+	return scheduledPrices, nil
+}
+
+// ApplyScheduledPrices restores the prices of entries whose window ended and
+// applies entries whose window started, in one transaction. The transaction
+// holds an advisory lock, and every entry moves to its next status in the
+// same transaction as its price change, so each entry is applied exactly once
+// across replicas.
+func (r *Postgres) ApplyScheduledPrices(ctx context.Context, now time.Time) (*models.ApplyScheduledPricesOutput, error) {
+	var output models.ApplyScheduledPricesOutput
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var locked bool
+		if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, scheduledPricesLockKey).Scan(&locked); err != nil {
+			r.logger.Errorf("Error acquiring scheduled prices lock: %v", err)
+			return err
+		}
+		if !locked {
+			output.Skipped = true
+			return nil
+		}
+
+		// Due entries of deleted products, and entries whose whole window
+		// passed before they could be applied, are closed without touching prices.
+		query := `UPDATE scheduled_prices s SET status = CASE WHEN p.deleted_at IS NULL THEN 'finished' ELSE 'cancelled' END, finished_at = now()
+			FROM products p
+			WHERE p.id = s.product_id
+			  AND ((s.status = 'pending' AND s.starts_at <= $1 AND (p.deleted_at IS NOT NULL OR s.ends_at <= $1))
+			    OR (s.status = 'active' AND s.ends_at <= $1 AND p.deleted_at IS NOT NULL))`
+		if _, err := tx.Exec(ctx, query, now); err != nil {
+			r.logger.Errorf("Error closing stale scheduled prices: %v", err)
+			return err
+		}
+
+		query = `SELECT id, product_id, COALESCE(price_list_id, 0), previous_price FROM scheduled_prices
+			WHERE status = 'active' AND ends_at <= $1
+			ORDER BY ends_at, id
+			FOR UPDATE`
+		rows, err := tx.Query(ctx, query, now)
+		if err != nil {
+			r.logger.Errorf("Error fetching ended scheduled prices: %v", err)
+			return err
+		}
+		ended, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (scheduledPriceChange, error) {
+			var change scheduledPriceChange
+			err := row.Scan(&change.id, &change.productID, &change.priceListID, &change.price)
+			return change, err
+		})
+		if err != nil {
+			r.logger.Errorf("Error scanning scheduled price row: %v", err)
+			return err
+		}
+
+		for _, change := range ended {
+			if _, err = r.setScheduledPrice(ctx, tx, change); err != nil {
+				return err
+			}
+			query = `UPDATE scheduled_prices SET status = 'finished', finished_at = now() WHERE id = $1`
+			if _, err = tx.Exec(ctx, query, change.id); err != nil {
+				r.logger.Errorf("Error finishing scheduled price: %v", err)
+				return err
+			}
+			output.Reverted++
+		}
+
+		query = `SELECT id, product_id, COALESCE(price_list_id, 0), price, ends_at IS NULL FROM scheduled_prices
+			WHERE status = 'pending' AND starts_at <= $1
+			ORDER BY starts_at, id
+			FOR UPDATE`
+		rows, err = tx.Query(ctx, query, now)
+		if err != nil {
+			r.logger.Errorf("Error fetching due scheduled prices: %v", err)
+			return err
+		}
+		due, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (scheduledPriceChange, error) {
+			var change scheduledPriceChange
+			var price float64
+			err := row.Scan(&change.id, &change.productID, &change.priceListID, &price, &change.permanent)
+			change.price = null.FloatFrom(price)
+			return change, err
+		})
+		if err != nil {
+			r.logger.Errorf("Error scanning scheduled price row: %v", err)
+			return err
+		}
+
+		for _, change := range due {
+			previous, err := r.setScheduledPrice(ctx, tx, change)
+			if err != nil {
+				return err
+			}
+			// Permanent changes have nothing to restore and finish right away.
+			query = `UPDATE scheduled_prices SET status = CASE WHEN $3 THEN 'finished' ELSE 'active' END,
+				previous_price = $2, applied_at = now(), finished_at = CASE WHEN $3 THEN now() END
+				WHERE id = $1`
+			if _, err = tx.Exec(ctx, query, change.id, previous, change.permanent); err != nil {
+				r.logger.Errorf("Error activating scheduled price: %v", err)
+				return err
+			}
+			output.Applied++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+type scheduledPriceChange struct {
+	id          int64
+	productID   int64
+	priceListID int64
+	// price is the price to set; NULL removes the product from the price list.
+	price     null.Float
+	permanent bool
+}
+
+// setScheduledPrice sets a product or price list price on behalf of a
+// scheduled entry and returns the price it replaced.
+func (r *Postgres) setScheduledPrice(ctx context.Context, tx pgx.Tx, change scheduledPriceChange) (null.Float, error) {
+	var previous null.Float
+
+	query := `SELECT set_config('products.scheduled_price_id', $1, true)`
+	if _, err := tx.Exec(ctx, query, strconv.FormatInt(change.id, 10)); err != nil {
+		r.logger.Errorf("Error tagging scheduled price change: %v", err)
+		return previous, err
+	}
+
+	if change.priceListID == 0 {
+		before, err := r.lockProduct(ctx, tx, change.productID)
+		if err != nil {
+			return previous, err
+		}
+
+		var after []byte
+		query = `UPDATE products p SET price = $2 FROM (SELECT price FROM products WHERE id = $1) old
+			WHERE p.id = $1 RETURNING old.price, to_jsonb(p)`
+		if err = tx.QueryRow(ctx, query, change.productID, change.price.Float64).Scan(&previous, &after); err != nil {
+			r.logger.Errorf("Error applying scheduled price: %v", err)
+			return previous, err
+		}
+		return previous, r.writeAudit(ctx, tx, models.AuditEntityProduct, change.productID, models.AuditOperationUpdate, before, after)
+	}
+
+	return r.setPriceListPrice(ctx, tx, change.priceListID, change.productID, change.price)
+}
+
+func (r *Postgres) GetPriceHistory(ctx context.Context, input *models.GetPriceHistoryInput) ([]*models.PriceHistoryEntry, int64, error) {
+	from := null.NewTime(input.From, !input.From.IsZero())
+	to := null.NewTime(input.To, !input.To.IsZero())
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM price_history
+		WHERE product_id = $1 AND price_list_id IS NOT DISTINCT FROM $2
+		  AND ($3::timestamptz IS NULL OR changed_at >= $3)
+		  AND ($4::timestamptz IS NULL OR changed_at < $4)`
+	err := r.db.QueryRowContext(ctx, countQuery, input.ProductID, nullID(input.PriceListID), from, to).Scan(&total)
+	if err != nil {
+		r.logger.Errorf("Error counting price history: %v", err)
+		return nil, 0, err
+	}
+
+	query := `SELECT id, product_id, COALESCE(price_list_id, 0), old_price, new_price, COALESCE(scheduled_price_id, 0), changed_at FROM price_history
+		WHERE product_id = $1 AND price_list_id IS NOT DISTINCT FROM $2
+		  AND ($3::timestamptz IS NULL OR changed_at >= $3)
+		  AND ($4::timestamptz IS NULL OR changed_at < $4)
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $5 OFFSET $6`
+	rows, err := r.db.QueryContext(ctx, query, input.ProductID, nullID(input.PriceListID), from, to, input.Limit, input.Offset)
+	if err != nil {
+		r.logger.Errorf("Error fetching price history: %v", err)
+		return nil, 0, err
+	}
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.PriceHistoryEntry, error) {
+		var entry models.PriceHistoryEntry
+		var oldPrice, newPrice null.Float
+		err := row.Scan(&entry.ID, &entry.ProductID, &entry.PriceListID, &oldPrice, &newPrice, &entry.ScheduledPriceID, &entry.ChangedAt)
+		entry.OldPrice = oldPrice.Ptr()
+		entry.NewPrice = newPrice.Ptr()
+		return &entry, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning price history row: %v", err)
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func scanScheduledPrice(row pgx.Row) (*models.ScheduledPrice, error) {
+	var scheduledPrice models.ScheduledPrice
+	var endsAt null.Time
+	var status string
+	err := row.Scan(&scheduledPrice.ID, &scheduledPrice.ProductID, &scheduledPrice.PriceListID, &scheduledPrice.Price,
+		&scheduledPrice.StartsAt, &endsAt, &status, &scheduledPrice.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	scheduledPrice.EndsAt = endsAt.Time
+	scheduledPrice.Status = models.ScheduledPriceStatus(status)
+	return &scheduledPrice, nil
+}
@@ -0,0 +1,174 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/pkg/slug"
+)
+
+// slugScope is a table whose slugs are unique together with the slugs its
+// rows had before.
+type slugScope struct {
+	entity    string
+	table     string
+	redirects string
+	key       string
+}
+
+var (
+	productSlugs  = slugScope{entity: "product", table: "products", redirects: "product_slug_redirects", key: "product_id"}
+	categorySlugs = slugScope{entity: "category", table: "product_categories", redirects: "category_slug_redirects", key: "category_id"}
+)
+
+// assignSlug returns the slug for row id, zero for a row not inserted yet.
+// An explicit slug must not belong to another row; otherwise the slug is
+// generated from name and made unique with a numeric suffix. Former slugs of
+// other rows count as taken, the row's own former slugs may be reused.
+func (r *Postgres) assignSlug(ctx context.Context, tx pgx.Tx, scope slugScope, id int64, explicit, name string) (string, error) {
+	base := explicit
+	if base == "" {
+		if base = slug.Make(name); base == "" {
+			base = scope.entity
+		}
+	}
+
+	// Serializes slug assignment within the scope so that the free slug
+	// found below is still free when the row is written.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, scope.table+".slug"); err != nil {
+		r.logger.Errorf("Error acquiring %s slug lock: %v", scope.entity, err)
+		return "", err
+	}
+
+	query := fmt.Sprintf(`SELECT slug FROM %[1]s WHERE id <> $1 AND (slug = $2 OR slug LIKE $2 || '-%%')
+		UNION SELECT slug FROM %[2]s WHERE %[3]s <> $1 AND (slug = $2 OR slug LIKE $2 || '-%%')`,
+		scope.table, scope.redirects, scope.key)
+	rows, err := tx.Query(ctx, query, id, base)
+	if err != nil {
+		r.logger.Errorf("Error fetching %s slugs: %v", scope.entity, err)
+		return "", err
+	}
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		r.logger.Errorf("Error scanning %s slug row: %v", scope.entity, err)
+		return "", err
+	}
+	taken := make(map[string]bool, len(slugs))
+	for _, s := range slugs {
+		taken[s] = true
+	}
+
+	if explicit != "" {
+		if taken[explicit] {
+			return "", fmt.Errorf("slug %q is already taken", explicit)
+		}
+		return explicit, nil
+	}
+	candidate := base
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	return candidate, nil
+}
+
+// updatedSlug returns the slug of the locked row id after an update setting
+// its name. The slug changes when an explicit one is given or the name
+// changes; the former slug is then kept as a redirect.
+func (r *Postgres) updatedSlug(ctx context.Context, tx pgx.Tx, scope slugScope, id int64, explicit, name string) (string, error) {
+	var currentName, current string
+	query := fmt.Sprintf(`SELECT name, slug FROM %s WHERE id = $1`, scope.table)
+	if err := tx.QueryRow(ctx, query, id).Scan(&currentName, &current); err != nil {
+		r.logger.Errorf("Error fetching %s slug: %v", scope.entity, err)
+		return "", err
+	}
+	if explicit == "" && name == currentName {
+		return current, nil
+	}
+
+	s, err := r.assignSlug(ctx, tx, scope, id, explicit, name)
+	if err != nil {
+		return "", err
+	}
+	if err = r.moveSlug(ctx, tx, scope, id, current, s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// moveSlug keeps the former slug of row id as a redirect when the row moves
+// to another slug, and drops the redirect of a former slug it takes back.
+func (r *Postgres) moveSlug(ctx context.Context, tx pgx.Tx, scope slugScope, id int64, from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE slug = $1`, scope.redirects), to); err != nil {
+		r.logger.Errorf("Error deleting %s slug redirect: %v", scope.entity, err)
+		return err
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (slug, %s) VALUES ($1, $2) ON CONFLICT (slug) DO NOTHING`, scope.redirects, scope.key)
+	if _, err := tx.Exec(ctx, query, from, id); err != nil {
+		r.logger.Errorf("Error creating %s slug redirect: %v", scope.entity, err)
+		return err
+	}
+	return nil
+}
+
+// resolveSlug returns the row with the slug and whether it is a former slug
+// of that row.
+func (r *Postgres) resolveSlug(ctx context.Context, scope slugScope, s string) (int64, bool, error) {
+	var id int64
+	var redirected bool
+
+	query := fmt.Sprintf(`SELECT id, false FROM %[1]s WHERE slug = $1
+		UNION ALL
+		SELECT %[3]s, true FROM %[2]s WHERE slug = $1
+		LIMIT 1`, scope.table, scope.redirects, scope.key)
+	err := r.db.QueryRowContext(ctx, query, s).Scan(&id, &redirected)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, fmt.Errorf("%s not found", scope.entity)
+		}
+		r.logger.Errorf("Error resolving %s slug: %v", scope.entity, err)
+		return 0, false, err
+	}
+
+	return id, redirected, nil
+}
+
+func (r *Postgres) getSlug(ctx context.Context, scope slugScope, id int64) (string, error) {
+	var s string
+
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT slug FROM %s WHERE id = $1`, scope.table), id).Scan(&s)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s not found", scope.entity)
+		}
+		r.logger.Errorf("Error fetching %s slug: %v", scope.entity, err)
+		return "", err
+	}
+
+	return s, nil
+}
+
+// GetProductIDBySlug resolves a current or former product slug; redirected
+// tells that it was a former one.
+func (r *Postgres) GetProductIDBySlug(ctx context.Context, s string) (id int64, redirected bool, err error) {
+	return r.resolveSlug(ctx, productSlugs, s)
+}
+
+func (r *Postgres) GetProductSlug(ctx context.Context, productID int64) (string, error) {
+	return r.getSlug(ctx, productSlugs, productID)
+}
+
+// GetCategoryIDBySlug resolves a current or former category slug; redirected
+// tells that it was a former one.
+func (r *Postgres) GetCategoryIDBySlug(ctx context.Context, s string) (id int64, redirected bool, err error) {
+	return r.resolveSlug(ctx, categorySlugs, s)
+}
+
+func (r *Postgres) GetCategorySlug(ctx context.Context, categoryID int64) (string, error) {
+	return r.getSlug(ctx, categorySlugs, categoryID)
+}
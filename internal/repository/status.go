@@ -0,0 +1,119 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"products/pkg/reqctx"
+)
+
+func (r *Postgres) GetProductStatus(ctx context.Context, productID int64) (models.ProductStatus, error) {
+	var status string
+
+	err := r.db.QueryRowContext(ctx, `SELECT status FROM products WHERE id = $1 AND deleted_at IS NULL`, productID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error fetching product status: %v", err)
+		return "", err
+	}
+
+	return models.ProductStatus(status), nil
+}
+
+// TransitionProductStatus moves a product from one status to another and
+// records the transition. It fails when the product is no longer in from, so
+// a transition checked against a stale status is never applied.
+func (r *Postgres) TransitionProductStatus(ctx context.Context, input *models.TransitionProductStatusInput, from models.ProductStatus) (*models.ProductTransition, error) {
+	var transition *models.ProductTransition
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		transition, err = r.transitionProductStatus(ctx, tx, input, from)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transition, nil
+}
+
+func (r *Postgres) transitionProductStatus(ctx context.Context, tx pgx.Tx, input *models.TransitionProductStatusInput, from models.ProductStatus) (*models.ProductTransition, error) {
+	transition := models.ProductTransition{
+		ProductID: input.ProductID,
+		From:      from,
+		To:        input.To,
+		Actor:     reqctx.Actor(ctx),
+		Role:      reqctx.Role(ctx),
+		Reason:    input.Reason,
+		RequestID: reqctx.RequestID(ctx),
+	}
+
+	before, err := r.lockProduct(ctx, tx, input.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if input.To == models.ProductStatusDiscontinued || input.To == models.ProductStatusArchived {
+		if err = r.checkBundleComponent(ctx, tx, input.ProductID, true); err != nil {
+			return nil, err
+		}
+	}
+
+	var after []byte
+	query := `UPDATE products p SET status = $2 WHERE id = $1 AND status = $3 RETURNING to_jsonb(p)`
+	err = tx.QueryRow(ctx, query, input.ProductID, string(input.To), string(from)).Scan(&after)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product status changed concurrently")
+		}
+		r.logger.Errorf("Error updating product status: %v", err)
+		return nil, err
+	}
+
+	query = `INSERT INTO product_status_transitions (product_id, from_status, to_status, actor, role, reason, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	err = tx.QueryRow(ctx, query, input.ProductID, string(from), string(input.To),
+		null.NewString(transition.Actor, transition.Actor != ""), null.NewString(transition.Role, transition.Role != ""),
+		input.Reason, null.NewString(transition.RequestID, transition.RequestID != "")).Scan(&transition.ID, &transition.CreatedAt)
+	if err != nil {
+		r.logger.Errorf("Error recording product status transition: %v", err)
+		return nil, err
+	}
+	if err = r.writeAudit(ctx, tx, models.AuditEntityProduct, input.ProductID, models.AuditOperationUpdate, before, after); err != nil {
+		return nil, err
+	}
+
+	return &transition, nil
+}
+
+func (r *Postgres) GetProductTransitions(ctx context.Context, productID int64) ([]*models.ProductTransition, error) {
+	query := `SELECT id, product_id, from_status, to_status, COALESCE(actor, ''), COALESCE(role, ''), reason, COALESCE(request_id, ''), created_at
+		FROM product_status_transitions WHERE product_id = $1 ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product status transitions: %v", err)
+		return nil, err
+	}
+
+	transitions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.ProductTransition, error) {
+		var transition models.ProductTransition
+		var from, to string
+		err := row.Scan(&transition.ID, &transition.ProductID, &from, &to, &transition.Actor, &transition.Role,
+			&transition.Reason, &transition.RequestID, &transition.CreatedAt)
+		transition.From = models.ProductStatus(from)
+		transition.To = models.ProductStatus(to)
+		return &transition, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning product status transition row: %v", err)
+		return nil, err
+	}
+
+	return transitions, nil
+}
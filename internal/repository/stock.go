@@ -0,0 +1,167 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"products/pkg/reqctx"
+)
+
+func (r *Postgres) CreateWarehouse(ctx context.Context, input *models.Warehouse) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+
+	query := `INSERT INTO warehouses (code, name, active) VALUES ($1, $2, $3) RETURNING id, code, name, active`
+	err := r.db.QueryRowContext(ctx, query, input.Code, input.Name, input.Active).Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Active)
+	if err != nil {
+		if isConstraintViolation(err, uniqueViolationCode, "warehouses_code_key") {
+			return nil, fmt.Errorf("warehouse %q already exists", input.Code)
+		}
+		r.logger.Errorf("Error creating warehouse: %v", err)
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+func (r *Postgres) UpdateWarehouse(ctx context.Context, input *models.Warehouse) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+
+	query := `UPDATE warehouses SET code = $1, name = $2, active = $3 WHERE id = $4 RETURNING id, code, name, active`
+	err := r.db.QueryRowContext(ctx, query, input.Code, input.Name, input.Active, input.ID).Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Active)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("warehouse not found")
+		}
+		if isConstraintViolation(err, uniqueViolationCode, "warehouses_code_key") {
+			return nil, fmt.Errorf("warehouse %q already exists", input.Code)
+		}
+		r.logger.Errorf("Error updating warehouse: %v", err)
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+func (r *Postgres) GetWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	var warehouses []*models.Warehouse
+
+	query := `SELECT id, code, name, active FROM warehouses ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Errorf("Error fetching warehouses: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var warehouse models.Warehouse
+		if err = rows.Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Active); err != nil {
+			r.logger.Errorf("Error scanning warehouse row: %v", err)
+			return nil, err
+		}
+		warehouses = append(warehouses, &warehouse)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return warehouses, nil
+}
+
+// AdjustStock changes the on-hand quantity with a conditional update, so
+// concurrent adjustments can never take stock below the reserved quantity.
+func (r *Postgres) AdjustStock(ctx context.Context, input *models.AdjustStockInput) (*models.StockLevel, error) {
+	var level models.StockLevel
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := r.ensureStockLevel(ctx, tx, input.ProductID, input.WarehouseID); err != nil {
+			return err
+		}
+
+		query := `UPDATE stock_levels SET on_hand = on_hand + $3, updated_at = now()
+			WHERE product_id = $1 AND warehouse_id = $2 AND on_hand + $3 >= reserved
+			RETURNING product_id, warehouse_id, on_hand, reserved`
+		err := tx.QueryRow(ctx, query, input.ProductID, input.WarehouseID, input.Delta).Scan(&level.ProductID, &level.WarehouseID, &level.OnHand, &level.Reserved)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("insufficient stock")
+			}
+			r.logger.Errorf("Error adjusting stock: %v", err)
+			return err
+		}
+		level.Available = level.OnHand - level.Reserved
+
+		return r.writeStockMovement(ctx, tx, input.ProductID, input.WarehouseID, input.Delta, input.Reason, input.Reference)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+func (r *Postgres) GetProductAvailability(ctx context.Context, productID int64) ([]*models.StockLevel, error) {
+	var levels []*models.StockLevel
+
+	query := `SELECT s.product_id, s.warehouse_id, w.code, s.on_hand, s.reserved
+		FROM stock_levels s JOIN warehouses w ON w.id = s.warehouse_id
+		WHERE s.product_id = $1 AND w.active
+		ORDER BY w.code`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product availability: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var level models.StockLevel
+		if err = rows.Scan(&level.ProductID, &level.WarehouseID, &level.WarehouseCode, &level.OnHand, &level.Reserved); err != nil {
+			r.logger.Errorf("Error scanning stock level row: %v", err)
+			return nil, err
+		}
+		level.Available = level.OnHand - level.Reserved
+		levels = append(levels, &level)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// ensureStockLevel creates the zero stock row for a product in a warehouse.
+func (r *Postgres) ensureStockLevel(ctx context.Context, tx pgx.Tx, productID, warehouseID int64) error {
+	query := `INSERT INTO stock_levels (product_id, warehouse_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := tx.Exec(ctx, query, productID, warehouseID)
+	switch {
+	case err == nil:
+		return nil
+	case isConstraintViolation(err, foreignKeyViolationCode, "stock_levels_product_id_fkey"):
+		return fmt.Errorf("product not found")
+	case isConstraintViolation(err, foreignKeyViolationCode, "stock_levels_warehouse_id_fkey"):
+		return fmt.Errorf("warehouse not found")
+	}
+	r.logger.Errorf("Error creating stock level: %v", err)
+	return err
+}
+
+func (r *Postgres) writeStockMovement(ctx context.Context, tx pgx.Tx, productID, warehouseID, delta int64, reason models.StockReason, reference string) error {
+	actor := reqctx.Actor(ctx)
+
+	query := `INSERT INTO stock_movements (product_id, warehouse_id, delta, reason, reference, actor) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.Exec(ctx, query, productID, warehouseID, delta, string(reason), null.NewString(reference, reference != ""), null.NewString(actor, actor != ""))
+	if err != nil {
+		r.logger.Errorf("Error writing stock movement: %v", err)
+		return err
+	}
+	return nil
+}
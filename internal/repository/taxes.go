@@ -0,0 +1,191 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"time"
+)
+
+func (r *Postgres) CreateTaxClass(ctx context.Context, input *models.TaxClass) (*models.TaxClass, error) {
+	var taxClass models.TaxClass
+
+	query := `INSERT INTO tax_classes (code, name) VALUES ($1, $2) RETURNING id, code, name`
+	err := r.db.QueryRowContext(ctx, query, input.Code, input.Name).Scan(&taxClass.ID, &taxClass.Code, &taxClass.Name)
+	if err != nil {
+		if isConstraintViolation(err, uniqueViolationCode, "tax_classes_code_key") {
+			return nil, fmt.Errorf("tax class %q already exists", input.Code)
+		}
+		r.logger.Errorf("Error creating tax class: %v", err)
+		return nil, err
+	}
+
+	return &taxClass, nil
+}
+
+func (r *Postgres) GetTaxClasses(ctx context.Context) ([]*models.TaxClass, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, code, name FROM tax_classes ORDER BY code`)
+	if err != nil {
+		r.logger.Errorf("Error fetching tax classes: %v", err)
+		return nil, err
+	}
+
+	taxClasses, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.TaxClass, error) {
+		var taxClass models.TaxClass
+		err := row.Scan(&taxClass.ID, &taxClass.Code, &taxClass.Name)
+		return &taxClass, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning tax class row: %v", err)
+		return nil, err
+	}
+
+	return taxClasses, nil
+}
+
+func (r *Postgres) SetTaxRegion(ctx context.Context, input *models.TaxRegion) error {
+	query := `INSERT INTO tax_regions (code, name, display_gross) VALUES ($1, $2, $3)
+		ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, display_gross = EXCLUDED.display_gross`
+	if _, err := r.db.ExecContext(ctx, query, input.Code, input.Name, input.DisplayGross); err != nil {
+		r.logger.Errorf("Error setting tax region: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Postgres) GetTaxRegion(ctx context.Context, code string) (*models.TaxRegion, error) {
+	var region models.TaxRegion
+
+	query := `SELECT code, name, display_gross FROM tax_regions WHERE code = $1`
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&region.Code, &region.Name, &region.DisplayGross)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("tax region %q not found", code)
+		}
+		r.logger.Errorf("Error fetching tax region: %v", err)
+		return nil, err
+	}
+
+	return &region, nil
+}
+
+func (r *Postgres) CreateTaxRate(ctx context.Context, input *models.TaxRate) (*models.TaxRate, error) {
+	var taxRate models.TaxRate
+
+	query := `INSERT INTO tax_rates (tax_class_id, region_code, rate, effective_from) VALUES ($1, $2, $3, $4)
+		RETURNING id, tax_class_id, region_code, rate, effective_from`
+	err := r.db.QueryRowContext(ctx, query, input.TaxClassID, input.RegionCode, input.Rate, input.EffectiveFrom).
+		Scan(&taxRate.ID, &taxRate.TaxClassID, &taxRate.RegionCode, &taxRate.Rate, &taxRate.EffectiveFrom)
+	if err != nil {
+		switch {
+		case isConstraintViolation(err, uniqueViolationCode, "tax_rates_class_region_from_key"):
+			return nil, fmt.Errorf("tax rate already takes effect at %s", input.EffectiveFrom.Format(time.RFC3339))
+		case isConstraintViolation(err, foreignKeyViolationCode, "tax_rates_tax_class_id_fkey"):
+			return nil, fmt.Errorf("tax class not found")
+		case isConstraintViolation(err, foreignKeyViolationCode, "tax_rates_region_code_fkey"):
+			return nil, fmt.Errorf("tax region %q not found", input.RegionCode)
+		}
+		r.logger.Errorf("Error creating tax rate: %v", err)
+		return nil, err
+	}
+
+	return &taxRate, nil
+}
+
+func (r *Postgres) SetProductTaxClass(ctx context.Context, input *models.SetTaxClassInput) error {
+	var err error
+	if input.TaxClassID == 0 {
+		_, err = r.db.ExecContext(ctx, `DELETE FROM product_tax_classes WHERE product_id = $1`, input.ID)
+	} else {
+		query := `INSERT INTO product_tax_classes (product_id, tax_class_id) VALUES ($1, $2)
+			ON CONFLICT (product_id) DO UPDATE SET tax_class_id = EXCLUDED.tax_class_id`
+		_, err = r.db.ExecContext(ctx, query, input.ID, input.TaxClassID)
+	}
+	if err != nil {
+		switch {
+		case isConstraintViolation(err, foreignKeyViolationCode, "product_tax_classes_product_id_fkey"):
+			return fmt.Errorf("product not found")
+		case isConstraintViolation(err, foreignKeyViolationCode, "product_tax_classes_tax_class_id_fkey"):
+			return fmt.Errorf("tax class not found")
+		}
+		r.logger.Errorf("Error setting product tax class: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *Postgres) SetCategoryTaxClass(ctx context.Context, input *models.SetTaxClassInput) error {
+	var err error
+	if input.TaxClassID == 0 {
+		_, err = r.db.ExecContext(ctx, `DELETE FROM category_tax_classes WHERE category_id = $1`, input.ID)
+	} else {
+		query := `INSERT INTO category_tax_classes (category_id, tax_class_id) VALUES ($1, $2)
+			ON CONFLICT (category_id) DO UPDATE SET tax_class_id = EXCLUDED.tax_class_id`
+		_, err = r.db.ExecContext(ctx, query, input.ID, input.TaxClassID)
+	}
+	if err != nil {
+		switch {
+		case isConstraintViolation(err, foreignKeyViolationCode, "category_tax_classes_category_id_fkey"):
+			return fmt.Errorf("category not found")
+		case isConstraintViolation(err, foreignKeyViolationCode, "category_tax_classes_tax_class_id_fkey"):
+			return fmt.Errorf("tax class not found")
+		}
+		r.logger.Errorf("Error setting category tax class: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetProductTaxRates resolves each product's tax class (its own, else its
+// category's) and the rate of that class in effect in the region at the given
+// time. Products without a tax class, and products whose class has no rate
+// there, are reported as an error rather than taxed at zero.
+func (r *Postgres) GetProductTaxRates(ctx context.Context, region string, at time.Time, productIDs []int64) (map[int64]*models.ProductTaxRate, error) {
+	query := `SELECT p.id, COALESCE(tc.code, ''), rt.rate
+		FROM products p
+		LEFT JOIN product_tax_classes ptc ON ptc.product_id = p.id
+		LEFT JOIN category_tax_classes ctc ON ctc.category_id = p.category_id
+		LEFT JOIN tax_classes tc ON tc.id = COALESCE(ptc.tax_class_id, ctc.tax_class_id)
+		LEFT JOIN LATERAL (
+			SELECT rate FROM tax_rates
+			WHERE tax_class_id = tc.id AND region_code = $1 AND effective_from <= $2
+			ORDER BY effective_from DESC
+			LIMIT 1
+		) rt ON true
+		WHERE p.id = ANY($3)`
+	rows, err := r.db.QueryContext(ctx, query, region, at, productIDs)
+	if err != nil {
+		r.logger.Errorf("Error fetching product tax rates: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make(map[int64]*models.ProductTaxRate, len(productIDs))
+	for rows.Next() {
+		var rate models.ProductTaxRate
+		var value null.Float
+		if err = rows.Scan(&rate.ProductID, &rate.TaxClassCode, &value); err != nil {
+			r.logger.Errorf("Error scanning product tax rate row: %v", err)
+			return nil, err
+		}
+		if rate.TaxClassCode == "" {
+			return nil, fmt.Errorf("product %d has no tax class", rate.ProductID)
+		}
+		if !value.Valid {
+			return nil, fmt.Errorf("tax class %q has no rate in region %q", rate.TaxClassCode, region)
+		}
+		rate.Rate = value.Float64
+		rates[rate.ProductID] = &rate
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,33 @@
+package postgresql
+
+import (
+	"golang.org/x/net/context"
+	"products/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProductTaxRatesRejectsProductsWithoutTaxClass(t *testing.T) {
+	r := newTestRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, r.SetTaxRegion(ctx, &models.TaxRegion{Code: "RU", Name: "Russia", DisplayGross: true}))
+	taxClass, err := r.CreateTaxClass(ctx, &models.TaxClass{Code: "standard", Name: "Standard"})
+	require.NoError(t, err)
+	_, err = r.CreateTaxRate(ctx, &models.TaxRate{TaxClassID: taxClass.ID, RegionCode: "RU", Rate: 20, EffectiveFrom: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+
+	classified := createTestProduct(t, r, "Kettle", 100)
+	require.NoError(t, r.SetProductTaxClass(ctx, &models.SetTaxClassInput{ID: classified, TaxClassID: taxClass.ID}))
+	unclassified := createTestProduct(t, r, "Toaster", 100)
+
+	rates, err := r.GetProductTaxRates(ctx, "RU", time.Now(), []int64{classified})
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, rates[classified].Rate)
+
+	_, err = r.GetProductTaxRates(ctx, "RU", time.Now(), []int64{classified, unclassified})
+	assert.Error(t, err, "a product without a tax class must not be taxed at zero")
+}
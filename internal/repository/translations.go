@@ -0,0 +1,130 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+// localized selects column of the row aliased as alias in the first of the
+// locales (a text[] placeholder) that has a non-empty translation in table,
+// falling back to the row's own default-locale value.
+func localized(table, key, alias, column, locales string) string {
+	return fmt.Sprintf(`COALESCE((SELECT t.%[4]s FROM %[1]s t WHERE t.%[2]s = %[3]s.id AND t.locale = ANY(%[5]s::text[]) AND t.%[4]s <> ''
+		ORDER BY array_position(%[5]s::text[], t.locale) LIMIT 1), %[3]s.%[4]s)`, table, key, alias, column, locales)
+}
+
+func (r *Postgres) SetProductTranslation(ctx context.Context, input *models.SetProductTranslationInput) (*models.Translation, error) {
+	err := r.setTranslation(ctx, "product_translations", "product_id", models.AuditEntityProductTranslation, input.ProductID, input.Translation)
+	if err != nil {
+		if isConstraintViolation(err, foreignKeyViolationCode, "product_translations_product_id_fkey") {
+			return nil, fmt.Errorf("product not found")
+		}
+		r.logger.Errorf("Error setting product translation: %v", err)
+		return nil, err
+	}
+
+	translation := input.Translation
+	return &translation, nil
+}
+
+func (r *Postgres) DeleteProductTranslation(ctx context.Context, productID int64, locale string) error {
+	return r.deleteTranslation(ctx, "product_translations", "product_id", models.AuditEntityProductTranslation, productID, locale)
+}
+
+func (r *Postgres) GetProductTranslations(ctx context.Context, productID int64) ([]*models.Translation, error) {
+	query := `SELECT locale, name, description FROM product_translations WHERE product_id = $1 ORDER BY locale`
+	return r.getTranslations(ctx, query, productID)
+}
+
+func (r *Postgres) SetCategoryTranslation(ctx context.Context, input *models.SetCategoryTranslationInput) (*models.Translation, error) {
+	err := r.setTranslation(ctx, "category_translations", "category_id", models.AuditEntityCategoryTranslation, input.CategoryID, input.Translation)
+	if err != nil {
+		if isConstraintViolation(err, foreignKeyViolationCode, "category_translations_category_id_fkey") {
+			return nil, fmt.Errorf("category not found")
+		}
+		r.logger.Errorf("Error setting category translation: %v", err)
+		return nil, err
+	}
+
+	translation := input.Translation
+	return &translation, nil
+}
+
+func (r *Postgres) DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error {
+	return r.deleteTranslation(ctx, "category_translations", "category_id", models.AuditEntityCategoryTranslation, categoryID, locale)
+}
+
+func (r *Postgres) GetCategoryTranslations(ctx context.Context, categoryID int64) ([]*models.Translation, error) {
+	query := `SELECT locale, name, description FROM category_translations WHERE category_id = $1 ORDER BY locale`
+	return r.getTranslations(ctx, query, categoryID)
+}
+
+// setTranslation upserts a translation of the row keyed by id in table and
+// records the change in the audit log under entityType.
+func (r *Postgres) setTranslation(ctx context.Context, table, key, entityType string, id int64, translation models.Translation) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var before []byte
+		query := fmt.Sprintf(`SELECT to_jsonb(t) FROM %s t WHERE %s = $1 AND locale = $2 FOR UPDATE`, table, key)
+		err := tx.QueryRow(ctx, query, id, translation.Locale).Scan(&before)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Errorf("Error locking translation: %v", err)
+			return err
+		}
+
+		var after []byte
+		query = fmt.Sprintf(`INSERT INTO %[1]s AS t (%[2]s, locale, name, description) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (%[2]s, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description
+			RETURNING to_jsonb(t)`, table, key)
+		err = tx.QueryRow(ctx, query, id, translation.Locale, translation.Name, translation.Description).Scan(&after)
+		if err != nil {
+			return err
+		}
+
+		operation := models.AuditOperationUpdate
+		if before == nil {
+			operation = models.AuditOperationCreate
+		}
+		return r.writeAudit(ctx, tx, entityType, id, operation, before, after)
+	})
+}
+
+// deleteTranslation deletes a translation like setTranslation sets it.
+func (r *Postgres) deleteTranslation(ctx context.Context, table, key, entityType string, id int64, locale string) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var before []byte
+		query := fmt.Sprintf(`DELETE FROM %s t WHERE %s = $1 AND locale = $2 RETURNING to_jsonb(t)`, table, key)
+		err := tx.QueryRow(ctx, query, id, locale).Scan(&before)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("translation not found")
+			}
+			r.logger.Errorf("Error deleting translation: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, entityType, id, models.AuditOperationDelete, before, nil)
+	})
+}
+
+func (r *Postgres) getTranslations(ctx context.Context, query string, id int64) ([]*models.Translation, error) {
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		r.logger.Errorf("Error fetching translations: %v", err)
+		return nil, err
+	}
+
+	translations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Translation, error) {
+		var translation models.Translation
+		err := row.Scan(&translation.Locale, &translation.Name, &translation.Description)
+		return &translation, err
+	})
+	if err != nil {
+		r.logger.Errorf("Error scanning translation row: %v", err)
+		return nil, err
+	}
+
+	return translations, nil
+}
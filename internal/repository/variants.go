@@ -0,0 +1,196 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/net/context"
+	"products/internal/models"
+)
+
+func (r *Postgres) SetProductOptionAxes(ctx context.Context, input *models.SetProductOptionAxesInput) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+
+		var variants int64
+		err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM product_variants WHERE product_id = $1`, input.ProductID).Scan(&variants)
+		if err != nil {
+			r.logger.Errorf("Error counting product variants: %v", err)
+			return err
+		}
+		if variants > 0 {
+			return fmt.Errorf("option axes cannot change while the product has %d variants", variants)
+		}
+
+		if _, err = tx.Exec(ctx, `DELETE FROM product_option_axes WHERE product_id = $1`, input.ProductID); err != nil {
+			r.logger.Errorf("Error deleting product option axes: %v", err)
+			return err
+		}
+
+		for i, name := range input.Axes {
+			query := `INSERT INTO product_option_axes (product_id, name, position) VALUES ($1, $2, $3)`
+			if _, err = tx.Exec(ctx, query, input.ProductID, name, i); err != nil {
+				r.logger.Errorf("Error creating product option axis: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Postgres) GetProductOptionAxes(ctx context.Context, productID int64) ([]string, error) {
+	var axes []string
+
+	query := `SELECT name FROM product_option_axes WHERE product_id = $1 ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product option axes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			r.logger.Errorf("Error scanning product option axis row: %v", err)
+			return nil, err
+		}
+		axes = append(axes, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return axes, nil
+}
+
+func (r *Postgres) CreateVariant(ctx context.Context, input *models.CreateVariantInput) (*models.Variant, error) {
+	var variant models.Variant
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := r.lockProduct(ctx, tx, input.ProductID); err != nil {
+			return err
+		}
+
+		var after []byte
+		query := `INSERT INTO product_variants (product_id, sku, price, options, attributes) VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, product_id, sku, price, options, attributes, to_jsonb(product_variants)`
+		err := tx.QueryRow(ctx, query, input.ProductID, input.SKU, input.Price, input.Options, input.Attributes).
+			Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Price, &variant.Options, &variant.Attributes, &after)
+		if err != nil {
+			return r.variantError("creating", input.SKU, err)
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductVariant, variant.ID, models.AuditOperationCreate, nil, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (r *Postgres) GetVariant(ctx context.Context, id int64) (*models.Variant, error) {
+	var variant models.Variant
+
+	query := `SELECT id, product_id, sku, price, options, attributes FROM product_variants WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Price, &variant.Options, &variant.Attributes)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("variant not found")
+		}
+		r.logger.Errorf("Error fetching variant: %v", err)
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (r *Postgres) UpdateVariant(ctx context.Context, input *models.UpdateVariantInput) (*models.Variant, error) {
+	var variant models.Variant
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		var before []byte
+		err := tx.QueryRow(ctx, `SELECT to_jsonb(v) FROM product_variants v WHERE id = $1 FOR UPDATE`, input.ID).Scan(&before)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("variant not found")
+			}
+			r.logger.Errorf("Error locking variant: %v", err)
+			return err
+		}
+
+		var after []byte
+		query := `UPDATE product_variants v SET sku = $1, price = $2, options = $3, attributes = $4 WHERE id = $5
+			RETURNING id, product_id, sku, price, options, attributes, to_jsonb(v)`
+		err = tx.QueryRow(ctx, query, input.SKU, input.Price, input.Options, input.Attributes, input.ID).
+			Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Price, &variant.Options, &variant.Attributes, &after)
+		if err != nil {
+			return r.variantError("updating", input.SKU, err)
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductVariant, variant.ID, models.AuditOperationUpdate, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (r *Postgres) DeleteVariant(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var before []byte
+		err := tx.QueryRow(ctx, `DELETE FROM product_variants v WHERE id = $1 RETURNING to_jsonb(v)`, id).Scan(&before)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("variant not found")
+			}
+			r.logger.Errorf("Error deleting variant: %v", err)
+			return err
+		}
+		return r.writeAudit(ctx, tx, models.AuditEntityProductVariant, id, models.AuditOperationDelete, before, nil)
+	})
+}
+
+func (r *Postgres) GetProductVariants(ctx context.Context, productID int64) ([]*models.Variant, error) {
+	var variants []*models.Variant
+
+	query := `SELECT id, product_id, sku, price, options, attributes FROM product_variants WHERE product_id = $1 ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		r.logger.Errorf("Error fetching product variants: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var variant models.Variant
+		if err = rows.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Price, &variant.Options, &variant.Attributes); err != nil {
+			r.logger.Errorf("Error scanning variant row: %v", err)
+			return nil, err
+		}
+		variants = append(variants, &variant)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Errorf("Error in rows iteration: %v", err)
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (r *Postgres) variantError(action, sku string, err error) error {
+	switch {
+	case isConstraintViolation(err, uniqueViolationCode, "product_variants_sku_key"):
+		return fmt.Errorf("sku %q already exists", sku)
+	case isConstraintViolation(err, uniqueViolationCode, "product_variants_options_key"):
+		return fmt.Errorf("variant with these options already exists")
+	}
+	r.logger.Errorf("Error %s variant: %v", action, err)
+	return err
+}
@@ -4,20 +4,165 @@ package internal
 
 import (
 	models2 "products/internal/models"
+	"time"
 
 	"golang.org/x/net/context"
 )
 
 // Controller describes methods, implemented by the usecase package.
 type UseCase interface {
+	CreateCategoryAttribute(ctx context.Context, input *models2.AttributeDefinition) (*models2.AttributeDefinitionOutput, error)
+	UpdateCategoryAttribute(ctx context.Context, input *models2.AttributeDefinition) (*models2.AttributeDefinitionOutput, error)
+	DeleteCategoryAttribute(ctx context.Context, id int64) error
+	GetCategoryAttributes(ctx context.Context, categoryID int64) (*models2.GetCategoryAttributesOutput, error)
+	GetProductAttributes(ctx context.Context, productID int64) (*models2.GetProductAttributesOutput, error)
+	GetProductHistory(ctx context.Context, input *models2.GetProductHistoryInput) (*models2.GetProductHistoryOutput, error)
+	// ApplyAvailabilityWindows moves products along the status workflow as their
+	// availability windows close and reopen. A product changed concurrently,
+	// for instance by another replica, is skipped.
+	ApplyAvailabilityWindows(ctx context.Context) (*models2.ApplyAvailabilityWindowsOutput, error)
+	AddBarcode(ctx context.Context, input *models2.AddBarcodeInput) (*models2.BarcodeOutput, error)
+	DeleteBarcode(ctx context.Context, id int64) error
+	GetProductBarcodes(ctx context.Context, productID int64) (*models2.GetBarcodesOutput, error)
+	// ImportBarcodes adds every valid row and reports each row that was skipped,
+	// whether for a malformed code, a wrong check digit, a barcode repeated in
+	// the import or already assigned, or an unknown product or variant. Any other
+	// failure, such as a lost database connection, fails the whole import and
+	// adds none of the rows.
+	ImportBarcodes(ctx context.Context, input *models2.ImportBarcodesInput) (*models2.ImportBarcodesOutput, error)
+	// GetProductByBarcode returns the product that a barcode identifies, with
+	// the variant when the barcode belongs to one.
+	GetProductByBarcode(ctx context.Context, input *models2.GetProductByBarcodeInput) (*models2.GetProductByBarcodeOutput, error)
+	CreateBrand(ctx context.Context, input *models2.CreateBrandInput) (*models2.BrandOutput, error)
+	GetBrand(ctx context.Context, input *models2.GetBrandInput) (*models2.BrandOutput, error)
+	UpdateBrand(ctx context.Context, input *models2.UpdateBrandInput) (*models2.BrandOutput, error)
+	DeleteBrand(ctx context.Context, id int64) error
+	RestoreBrand(ctx context.Context, id int64) (*models2.BrandOutput, error)
+	GetBrands(ctx context.Context, input *models2.GetBrandsInput) (*models2.GetBrandsOutput, error)
+	GetBrandRollups(ctx context.Context, input *models2.GetBrandRollupsInput) (*models2.GetBrandRollupsOutput, error)
+	SetBundle(ctx context.Context, input *models2.Bundle) (*models2.BundleOutput, error)
+	DeleteBundle(ctx context.Context, productID int64) error
+	GetBundle(ctx context.Context, productID int64) (*models2.BundleOutput, error)
+	CreateCustomerGroup(ctx context.Context, input *models2.CustomerGroup) (*models2.CustomerGroupOutput, error)
+	UpdateCustomerGroup(ctx context.Context, input *models2.CustomerGroup) (*models2.CustomerGroupOutput, error)
+	GetCustomerGroups(ctx context.Context) (*models2.GetCustomerGroupsOutput, error)
+	SetProductVisibility(ctx context.Context, input *models2.SetProductVisibilityInput) error
+	GetProductFacets(ctx context.Context, input *models2.GetProductFacetsInput) (*models2.GetProductFacetsOutput, error)
+	SetProductReorderThreshold(ctx context.Context, input *models2.SetReorderThresholdInput) error
+	SetCategoryReorderThreshold(ctx context.Context, input *models2.SetReorderThresholdInput) error
+	ListLowStockProducts(ctx context.Context, input *models2.ListLowStockProductsInput) (*models2.ListLowStockProductsOutput, error)
+	EvaluateLowStock(ctx context.Context) (*models2.EvaluateLowStockOutput, error)
+	// AddProductMedia registers an image in the product's gallery together with
+	// its thumbnail. Without a configured thumbnail service the image is stored
+	// without one.
+	AddProductMedia(ctx context.Context, input *models2.AddProductMediaInput) (*models2.MediaOutput, error)
+	GetProductMedia(ctx context.Context, productID int64) (*models2.GetProductMediaOutput, error)
+	SetPrimaryMedia(ctx context.Context, id int64) error
+	ReorderProductMedia(ctx context.Context, input *models2.ReorderProductMediaInput) error
+	DeleteProductMedia(ctx context.Context, id int64) error
+	// RegenerateMediaThumbnail asks the thumbnail service again, e.g. for media
+	// registered while the service was not configured.
+	RegenerateMediaThumbnail(ctx context.Context, id int64) (*models2.MediaOutput, error)
+	GetThumbnail(ctx context.Context, ref string) ([]byte, error)
+	CreatePriceList(ctx context.Context, input *models2.PriceList) (*models2.PriceListOutput, error)
+	UpdatePriceList(ctx context.Context, input *models2.PriceList) (*models2.PriceListOutput, error)
+	GetPriceLists(ctx context.Context) (*models2.GetPriceListsOutput, error)
+	SetPriceListPrice(ctx context.Context, input *models2.SetPriceListPriceInput) error
+	SetExchangeRate(ctx context.Context, input *models2.ExchangeRate) error
+	// GetProductPrice resolves the price of a product in a price list, or in the
+	// first public price list of a currency. Without either it returns the base
+	// price, converted when another currency is asked for. The tier covering the
+	// quantity replaces the base price, and a price the list or one of its base
+	// lists sets for the product takes precedence over tiers. The product_price
+	// database function does the arithmetic, so the price matches the one
+	// listings and price filters use.
+	GetProductPrice(ctx context.Context, input *models2.GetProductPriceInput) (*models2.ProductPrice, error)
+	// SetPriceTiers replaces a product's tiers. Tiers must not overlap, and only
+	// the last tier may be open-ended.
+	SetPriceTiers(ctx context.Context, input *models2.SetPriceTiersInput) (*models2.GetPriceTiersOutput, error)
+	GetPriceTiers(ctx context.Context, productID int64) (*models2.GetPriceTiersOutput, error)
+	// GetQuantityPrice returns the unit price and line total for a quantity,
+	// resolved by GetProductPrice.
+	GetQuantityPrice(ctx context.Context, input *models2.GetQuantityPriceInput) (*models2.QuantityPrice, error)
+	CreatePromotion(ctx context.Context, input *models2.Promotion) (*models2.PromotionOutput, error)
+	UpdatePromotion(ctx context.Context, input *models2.Promotion) (*models2.PromotionOutput, error)
+	DeletePromotion(ctx context.Context, id int64) error
+	GetPromotions(ctx context.Context) (*models2.GetPromotionsOutput, error)
+	// QuotePrices prices every item, including its quantity tier, and applies the running promotions to it in
+	// priority order.
+	QuotePrices(ctx context.Context, input *models2.QuotePricesInput) (*models2.QuotePricesOutput, error)
+	AddProductRelation(ctx context.Context, input *models2.AddProductRelationInput) (*models2.ProductRelation, error)
+	SetProductRelations(ctx context.Context, input *models2.SetProductRelationsInput) (*models2.GetProductRelationsOutput, error)
+	DeleteProductRelation(ctx context.Context, relation *models2.ProductRelation) error
+	GetProductRelations(ctx context.Context, productID int64) (*models2.GetProductRelationsOutput, error)
+	// ReserveStock holds stock for the items. A bundle holds its components in
+	// the same warehouse, so the reservation lists the components in its place.
+	ReserveStock(ctx context.Context, input *models2.ReserveStockInput) (*models2.ReservationOutput, error)
+	CommitReservation(ctx context.Context, id string) (*models2.ReservationOutput, error)
+	ReleaseReservation(ctx context.Context, id string) (*models2.ReservationOutput, error)
+	// ReleaseExpiredReservations expires held reservations in batches until none are left.
+	ReleaseExpiredReservations(ctx context.Context) (*models2.ReleaseExpiredReservationsOutput, error)
+	GetProductRevisions(ctx context.Context, productID int64) (*models2.GetProductRevisionsOutput, error)
+	DiffProductVersions(ctx context.Context, input *models2.DiffProductVersionsInput) (*models2.DiffProductVersionsOutput, error)
+	// RevertProduct writes the fields of an earlier revision back to the product,
+	// which records a new revision.
+	RevertProduct(ctx context.Context, input *models2.RevertProductInput) (*models2.UpdateProductOutput, error)
+	CreateScheduledPrice(ctx context.Context, input *models2.ScheduledPrice) (*models2.ScheduledPriceOutput, error)
+	CancelScheduledPrice(ctx context.Context, id int64) (*models2.ScheduledPriceOutput, error)
+	GetScheduledPrices(ctx context.Context, input *models2.GetScheduledPricesInput) (*models2.GetScheduledPricesOutput, error)
+	ApplyScheduledPrices(ctx context.Context) (*models2.ApplyScheduledPricesOutput, error)
+	GetPriceHistory(ctx context.Context, input *models2.GetPriceHistoryInput) (*models2.GetPriceHistoryOutput, error)
+	// GetProductBySlug returns the product with the slug. A former slug of the
+	// product resolves too, with Redirected set so that callers can redirect to
+	// the current one.
+	GetProductBySlug(ctx context.Context, input *models2.GetProductBySlugInput) (*models2.GetProductOutput, error)
+	// GetProductCategoryBySlug returns the category with the slug, resolving
+	// former slugs like GetProductBySlug.
+	GetProductCategoryBySlug(ctx context.Context, input *models2.GetProductCategoryBySlugInput) (*models2.GetProductCategoryOutput, error)
+	// TransitionProductStatus moves a product along the status workflow if the
+	// caller's role may perform the transition.
+	TransitionProductStatus(ctx context.Context, input *models2.TransitionProductStatusInput) (*models2.TransitionProductStatusOutput, error)
+	GetProductTransitions(ctx context.Context, productID int64) (*models2.GetProductTransitionsOutput, error)
+	CreateWarehouse(ctx context.Context, input *models2.Warehouse) (*models2.WarehouseOutput, error)
+	UpdateWarehouse(ctx context.Context, input *models2.Warehouse) (*models2.WarehouseOutput, error)
+	GetWarehouses(ctx context.Context) (*models2.GetWarehousesOutput, error)
+	AdjustStock(ctx context.Context, input *models2.AdjustStockInput) (*models2.AdjustStockOutput, error)
+	GetProductAvailability(ctx context.Context, productID int64) (*models2.GetProductAvailabilityOutput, error)
+	CreateTaxClass(ctx context.Context, input *models2.TaxClass) (*models2.TaxClassOutput, error)
+	GetTaxClasses(ctx context.Context) (*models2.GetTaxClassesOutput, error)
+	SetTaxRegion(ctx context.Context, input *models2.TaxRegion) error
+	CreateTaxRate(ctx context.Context, input *models2.TaxRate) (*models2.TaxRateOutput, error)
+	SetProductTaxClass(ctx context.Context, input *models2.SetTaxClassInput) error
+	SetCategoryTaxClass(ctx context.Context, input *models2.SetTaxClassInput) error
+	// ComputeTax splits line amounts into net, tax and gross with the rates in
+	// effect in the region. Amounts are taken as gross when PRICES_INCLUDE_TAX is
+	// set and as net otherwise; every line is rounded to cents on its own and the
+	// totals are sums of the rounded lines.
+	ComputeTax(ctx context.Context, input *models2.ComputeTaxInput) (*models2.ComputeTaxOutput, error)
+	SetProductTranslation(ctx context.Context, input *models2.SetProductTranslationInput) (*models2.TranslationOutput, error)
+	DeleteProductTranslation(ctx context.Context, productID int64, locale string) error
+	GetProductTranslations(ctx context.Context, productID int64) (*models2.GetTranslationsOutput, error)
+	SetCategoryTranslation(ctx context.Context, input *models2.SetCategoryTranslationInput) (*models2.TranslationOutput, error)
+	DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error
+	GetCategoryTranslations(ctx context.Context, categoryID int64) (*models2.GetTranslationsOutput, error)
 	CreateProductCategory(ctx context.Context, input *models2.CreateProductCategoryInput) (*models2.CreateProductCategoryOutput, error)
-	GetProductCategory(ctx context.Context, id int64) (*models2.GetProductCategoryOutput, error)
+	GetProductCategory(ctx context.Context, input *models2.GetProductCategoryInput) (*models2.GetProductCategoryOutput, error)
 	UpdateProductCategory(ctx context.Context, input *models2.UpdateProductCategoryInput) (*models2.UpdateProductCategoryOutput, error)
-	DeleteProductCategory(ctx context.Context, id int64) error
-	GetProductCategories(ctx context.Context) (*models2.GetProductCategoriesOutput, error)
+	DeleteProductCategory(ctx context.Context, input *models2.DeleteProductCategoryInput) (*models2.DeleteProductCategoryOutput, error)
+	RestoreProductCategory(ctx context.Context, id int64) (*models2.GetProductCategoryOutput, error)
+	GetProductCategories(ctx context.Context, input *models2.GetProductCategoriesInput) (*models2.GetProductCategoriesOutput, error)
 	CreateProduct(ctx context.Context, input *models2.CreateProductInput) (*models2.CreateProductOutput, error)
-	GetProduct(ctx context.Context, id int64) (*models2.GetProductOutput, error)
+	GetProduct(ctx context.Context, input *models2.GetProductInput) (*models2.GetProductOutput, error)
 	UpdateProduct(ctx context.Context, input *models2.UpdateProductInput) (*models2.UpdateProductOutput, error)
 	DeleteProduct(ctx context.Context, id int64) error
-	GetProducts(ctx context.Context) (*models2.GetProductsOutput, error)
+	RestoreProduct(ctx context.Context, id int64) (*models2.GetProductOutput, error)
+	GetProducts(ctx context.Context, input *models2.GetProductsInput) (*models2.GetProductsOutput, error)
+	PurgeDeleted(ctx context.Context, retention time.Duration) (*models2.PurgeDeletedOutput, error)
+	SetProductOptionAxes(ctx context.Context, input *models2.SetProductOptionAxesInput) (*models2.GetProductOptionAxesOutput, error)
+	GetProductOptionAxes(ctx context.Context, productID int64) (*models2.GetProductOptionAxesOutput, error)
+	CreateVariant(ctx context.Context, input *models2.CreateVariantInput) (*models2.VariantOutput, error)
+	GetVariant(ctx context.Context, id int64) (*models2.VariantOutput, error)
+	UpdateVariant(ctx context.Context, input *models2.UpdateVariantInput) (*models2.VariantOutput, error)
+	DeleteVariant(ctx context.Context, id int64) error
+	GetProductVariants(ctx context.Context, productID int64) (*models2.GetProductVariantsOutput, error)
 }
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"slices"
+	"strings"
+)
+
+func (u *UseCase) CreateCategoryAttribute(ctx context.Context, input *models2.AttributeDefinition) (*models2.AttributeDefinitionOutput, error) {
+	if err := validateAttributeDefinition(input); err != nil {
+		return nil, err
+	}
+
+	attribute, err := u.repo.CreateCategoryAttribute(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating category attribute: %v", err)
+		return nil, err
+	}
+
+	return &models2.AttributeDefinitionOutput{
+		Attribute: attribute,
+	}, nil
+}
+
+func (u *UseCase) UpdateCategoryAttribute(ctx context.Context, input *models2.AttributeDefinition) (*models2.AttributeDefinitionOutput, error) {
+	if err := validateAttributeDefinition(input); err != nil {
+		return nil, err
+	}
+
+	attribute, err := u.repo.UpdateCategoryAttribute(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating category attribute: %v", err)
+		return nil, err
+	}
+
+	return &models2.AttributeDefinitionOutput{
+		Attribute: attribute,
+	}, nil
+}
+
+func (u *UseCase) DeleteCategoryAttribute(ctx context.Context, id int64) error {
+	err := u.repo.DeleteCategoryAttribute(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error deleting category attribute: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetCategoryAttributes(ctx context.Context, categoryID int64) (*models2.GetCategoryAttributesOutput, error) {
+	attributes, err := u.repo.GetCategoryAttributes(ctx, categoryID)
+	if err != nil {
+		u.logger.Errorf("Error fetching category attributes: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetCategoryAttributesOutput{
+		Attributes: attributes,
+	}, nil
+}
+
+func (u *UseCase) GetProductAttributes(ctx context.Context, productID int64) (*models2.GetProductAttributesOutput, error) {
+	attributes, err := u.repo.GetProductAttributes(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product attributes: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductAttributesOutput{
+		Attributes: attributes,
+	}, nil
+}
+
+// validateProductAttributes checks attribute values against the attribute
+// schema of the category. Products without a category accept no attributes.
+func (u *UseCase) validateProductAttributes(ctx context.Context, categoryID int64, attributes map[string]any) error {
+	var schema []*models2.AttributeDefinition
+	if categoryID != 0 {
+		var err error
+		schema, err = u.repo.GetCategoryAttributes(ctx, categoryID)
+		if err != nil {
+			u.logger.Errorf("Error fetching category attributes: %v", err)
+			return err
+		}
+	}
+
+	definitions := make(map[string]*models2.AttributeDefinition, len(schema))
+	for _, definition := range schema {
+		definitions[definition.Code] = definition
+	}
+
+	for code, value := range attributes {
+		definition, ok := definitions[code]
+		if !ok {
+			return fmt.Errorf("attribute %q is not defined for the category", code)
+		}
+		if err := checkAttributeValue(definition, value); err != nil {
+			return err
+		}
+	}
+
+	for _, definition := range schema {
+		if _, ok := attributes[definition.Code]; definition.Required && !ok {
+			return fmt.Errorf("attribute %q is required", definition.Code)
+		}
+	}
+	return nil
+}
+
+func checkAttributeValue(definition *models2.AttributeDefinition, value any) error {
+	switch definition.Type {
+	case models2.AttributeTypeString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("attribute %q must be a string", definition.Code)
+		}
+	case models2.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("attribute %q must be a boolean", definition.Code)
+		}
+	case models2.AttributeTypeEnum:
+		s, ok := value.(string)
+		if !ok || !slices.Contains(definition.EnumValues, s) {
+			return fmt.Errorf("attribute %q must be one of %s", definition.Code, strings.Join(definition.EnumValues, ", "))
+		}
+	case models2.AttributeTypeNumber:
+		n, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("attribute %q must be a number", definition.Code)
+		}
+		if definition.Min != nil && n < *definition.Min {
+			return fmt.Errorf("attribute %q must be at least %g %s", definition.Code, *definition.Min, definition.Unit)
+		}
+		if definition.Max != nil && n > *definition.Max {
+			return fmt.Errorf("attribute %q must be at most %g %s", definition.Code, *definition.Max, definition.Unit)
+		}
+	default:
+		return fmt.Errorf("attribute %q has unknown type %q", definition.Code, definition.Type)
+	}
+	return nil
+}
+
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func validateAttributeDefinition(definition *models2.AttributeDefinition) error {
+	definition.Code = strings.TrimSpace(definition.Code)
+	if definition.Code == "" {
+		return fmt.Errorf("attribute code must not be empty")
+	}
+	if definition.Name == "" {
+		definition.Name = definition.Code
+	}
+
+	switch definition.Type {
+	case models2.AttributeTypeString, models2.AttributeTypeBoolean:
+	case models2.AttributeTypeEnum:
+		if len(definition.EnumValues) == 0 {
+			return fmt.Errorf("enum attribute %q needs at least one value", definition.Code)
+		}
+	case models2.AttributeTypeNumber:
+		if definition.Min != nil && definition.Max != nil && *definition.Min > *definition.Max {
+			return fmt.Errorf("attribute %q minimum is greater than its maximum", definition.Code)
+		}
+	default:
+		return fmt.Errorf("unknown attribute type %q", definition.Type)
+	}
+
+	if definition.Type != models2.AttributeTypeNumber && (definition.Min != nil || definition.Max != nil || definition.Unit != "") {
+		return fmt.Errorf("only number attributes have a unit and a range")
+	}
+	if definition.Type != models2.AttributeTypeEnum && len(definition.EnumValues) > 0 {
+		return fmt.Errorf("only enum attributes have values")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+func (u *UseCase) GetProductHistory(ctx context.Context, input *models2.GetProductHistoryInput) (*models2.GetProductHistoryOutput, error) {
+	if err := checkStaffRead(ctx); err != nil {
+		return nil, err
+	}
+	if input.Limit <= 0 {
+		input.Limit = defaultHistoryLimit
+	}
+	if input.Limit > maxHistoryLimit {
+		input.Limit = maxHistoryLimit
+	}
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if !input.From.IsZero() && !input.To.IsZero() && !input.From.Before(input.To) {
+		return nil, fmt.Errorf("history range start must be before its end")
+	}
+
+	records, total, err := u.repo.GetProductHistory(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching product history: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductHistoryOutput{
+		Records: records,
+		Total:   total,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"products/pkg/reqctx"
+	"time"
+)
+
+// availabilityActor is recorded on the status transitions made by the
+// availability scheduler.
+const availabilityActor = "availability-scheduler"
+
+// ApplyAvailabilityWindows moves products along the status workflow as their
+// availability windows close and reopen. A product changed concurrently,
+// for instance by another replica, is skipped.
+func (u *UseCase) ApplyAvailabilityWindows(ctx context.Context) (*models2.ApplyAvailabilityWindowsOutput, error) {
+	var output models2.ApplyAvailabilityWindowsOutput
+
+	due, err := u.repo.GetDueAvailabilityTransitions(ctx, time.Now())
+	if err != nil {
+		u.logger.Errorf("Error fetching due availability transitions: %v", err)
+		return nil, err
+	}
+
+	ctx = reqctx.WithRole(reqctx.WithActor(ctx, availabilityActor), models2.ActorRoleSystem)
+	for _, candidate := range due {
+		if err = checkProductTransition(candidate.From, candidate.To, models2.ActorRoleSystem); err != nil {
+			u.logger.Errorf("Error applying availability of product %d: %v", candidate.ProductID, err)
+			continue
+		}
+
+		reason := "availability window closed"
+		if candidate.To == models2.ProductStatusPublished {
+			reason = "availability window opened"
+		}
+		transition, err := u.repo.TransitionProductStatus(ctx, &models2.TransitionProductStatusInput{
+			ProductID: candidate.ProductID,
+			To:        candidate.To,
+			Reason:    reason,
+		}, candidate.From)
+		if err != nil {
+			u.logger.Errorf("Error applying availability of product %d: %v", candidate.ProductID, err)
+			continue
+		}
+		output.Transitions = append(output.Transitions, transition)
+	}
+
+	return &output, nil
+}
+
+func validateAvailabilityWindow(window models2.AvailabilityWindow) error {
+	if !window.From.IsZero() && !window.Until.IsZero() && !window.From.Before(window.Until) {
+		return fmt.Errorf("availability window must start before it ends")
+	}
+	return nil
+}
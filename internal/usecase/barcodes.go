@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"products/pkg/barcode"
+	"sort"
+)
+
+func (u *UseCase) AddBarcode(ctx context.Context, input *models2.AddBarcodeInput) (*models2.BarcodeOutput, error) {
+	parsed, err := barcode.Parse(input.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := u.repo.AddBarcode(ctx, input, parsed)
+	if err != nil {
+		u.logger.Errorf("Error adding barcode: %v", err)
+		return nil, err
+	}
+
+	return &models2.BarcodeOutput{
+		Barcode: b,
+	}, nil
+}
+
+func (u *UseCase) DeleteBarcode(ctx context.Context, id int64) error {
+	err := u.repo.DeleteBarcode(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error deleting barcode: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetProductBarcodes(ctx context.Context, productID int64) (*models2.GetBarcodesOutput, error) {
+	barcodes, err := u.repo.GetProductBarcodes(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product barcodes: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetBarcodesOutput{
+		Barcodes: barcodes,
+	}, nil
+}
+
+// ImportBarcodes adds every valid row and reports each row that was skipped,
+// whether for a malformed code, a wrong check digit, a barcode repeated in
+// the import or already assigned, or an unknown product or variant. Any other
+// failure, such as a lost database connection, fails the whole import and
+// adds none of the rows.
+func (u *UseCase) ImportBarcodes(ctx context.Context, input *models2.ImportBarcodesInput) (*models2.ImportBarcodesOutput, error) {
+	var skipped []*models2.BarcodeImportError
+	skip := func(i int, row *models2.AddBarcodeInput, err error) {
+		skipped = append(skipped, &models2.BarcodeImportError{Row: i, Code: row.Code, Error: err.Error()})
+	}
+
+	var rows []*models2.ParsedBarcodeRow
+	seen := make(map[string]int, len(input.Rows))
+	for i, row := range input.Rows {
+		parsed, err := barcode.Parse(row.Code)
+		if err != nil {
+			skip(i, row, err)
+			continue
+		}
+		if first, ok := seen[parsed.GTIN]; ok {
+			skip(i, row, fmt.Errorf("barcode %s repeats row %d", parsed.Code, first))
+			continue
+		}
+		seen[parsed.GTIN] = i
+		rows = append(rows, &models2.ParsedBarcodeRow{Row: i, Input: row, Parsed: parsed})
+	}
+
+	output, err := u.repo.ImportBarcodes(ctx, rows)
+	if err != nil {
+		u.logger.Errorf("Error importing barcodes: %v", err)
+		return nil, err
+	}
+	output.Errors = append(skipped, output.Errors...)
+	sort.SliceStable(output.Errors, func(i, j int) bool { return output.Errors[i].Row < output.Errors[j].Row })
+
+	return output, nil
+}
+
+// GetProductByBarcode returns the product that a barcode identifies, with
+// the variant when the barcode belongs to one.
+func (u *UseCase) GetProductByBarcode(ctx context.Context, input *models2.GetProductByBarcodeInput) (*models2.GetProductByBarcodeOutput, error) {
+	parsed, err := barcode.Parse(input.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := u.repo.GetBarcode(ctx, parsed.GTIN)
+	if err != nil {
+		u.logger.Errorf("Error fetching barcode: %v", err)
+		return nil, err
+	}
+
+	input.ID = b.ProductID
+	product, err := u.GetProduct(ctx, &input.GetProductInput)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &models2.GetProductByBarcodeOutput{
+		GetProductOutput: product,
+		Barcode:          b,
+	}
+	if b.VariantID != 0 {
+		if output.Variant, err = u.repo.GetVariant(ctx, b.VariantID); err != nil {
+			u.logger.Errorf("Error fetching variant: %v", err)
+			return nil, err
+		}
+	}
+	return output, nil
+}
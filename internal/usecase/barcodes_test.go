@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"errors"
+	"golang.org/x/net/context"
+	"products/config"
+	repository "products/internal"
+	models2 "products/internal/models"
+	"products/pkg/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// barcodeRepository fakes the barcode import of the repository; any other
+// repository method panics.
+type barcodeRepository struct {
+	repository.Postgres
+
+	rows   []*models2.ParsedBarcodeRow
+	output *models2.ImportBarcodesOutput
+	err    error
+}
+
+func (r *barcodeRepository) ImportBarcodes(_ context.Context, rows []*models2.ParsedBarcodeRow) (*models2.ImportBarcodesOutput, error) {
+	r.rows = rows
+	return r.output, r.err
+}
+
+func newBarcodeUseCase(t *testing.T, repo *barcodeRepository) *UseCase {
+	t.Helper()
+
+	log := logger.NewApiLogger(&config.Config{})
+	require.NoError(t, log.InitLogger())
+	return NewUseCase(&config.Config{}, repo, nil, log)
+}
+
+func TestImportBarcodesReportsRejectedRows(t *testing.T) {
+	repo := &barcodeRepository{output: &models2.ImportBarcodesOutput{
+		Barcodes: []*models2.Barcode{{ID: 1, Code: "4006381333931"}},
+		Errors:   []*models2.BarcodeImportError{{Row: 3, Code: "96385074", Error: "product not found"}},
+	}}
+	u := newBarcodeUseCase(t, repo)
+
+	output, err := u.ImportBarcodes(context.Background(), &models2.ImportBarcodesInput{Rows: []*models2.AddBarcodeInput{
+		{ProductID: 1, Code: "4006381333931"},
+		{ProductID: 1, Code: "4006381333932"},
+		{ProductID: 2, Code: "036000291452"},
+		{ProductID: 9, Code: "96385074"},
+		{ProductID: 3, Code: "0036000291452"},
+	}})
+	require.NoError(t, err)
+
+	// Rows 1 and 4 never reach the repository: a bad check digit, and the
+	// EAN-13 form of the UPC-A in row 2.
+	require.Len(t, repo.rows, 3)
+	assert.Equal(t, []int{0, 2, 3}, []int{repo.rows[0].Row, repo.rows[1].Row, repo.rows[2].Row})
+
+	require.Len(t, output.Errors, 3)
+	assert.Equal(t, 1, output.Errors[0].Row)
+	assert.Contains(t, output.Errors[0].Error, "check digit")
+	assert.Equal(t, 3, output.Errors[1].Row)
+	assert.Equal(t, "product not found", output.Errors[1].Error)
+	assert.Equal(t, 4, output.Errors[2].Row)
+	assert.Contains(t, output.Errors[2].Error, "repeats row 2")
+	assert.Len(t, output.Barcodes, 1)
+}
+
+func TestImportBarcodesFailsOnRepositoryError(t *testing.T) {
+	repo := &barcodeRepository{err: errors.New("connection refused")}
+	u := newBarcodeUseCase(t, repo)
+
+	output, err := u.ImportBarcodes(context.Background(), &models2.ImportBarcodesInput{Rows: []*models2.AddBarcodeInput{
+		{ProductID: 1, Code: "4006381333931"},
+		{ProductID: 1, Code: "bad"},
+	}})
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, output)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"strings"
+)
+
+func (u *UseCase) CreateBrand(ctx context.Context, input *models2.CreateBrandInput) (*models2.BrandOutput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, fmt.Errorf("brand name must not be empty")
+	}
+
+	brand, err := u.repo.CreateBrand(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating brand: %v", err)
+		return nil, err
+	}
+
+	return &models2.BrandOutput{
+		Brand: brand,
+	}, nil
+}
+
+func (u *UseCase) GetBrand(ctx context.Context, input *models2.GetBrandInput) (*models2.BrandOutput, error) {
+	if input.IncludeDeleted {
+		if err := checkStaffRead(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	brand, err := u.repo.GetBrand(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching brand: %v", err)
+		return nil, err
+	}
+
+	return &models2.BrandOutput{
+		Brand: brand,
+	}, nil
+}
+
+func (u *UseCase) UpdateBrand(ctx context.Context, input *models2.UpdateBrandInput) (*models2.BrandOutput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, fmt.Errorf("brand name must not be empty")
+	}
+
+	brand, err := u.repo.UpdateBrand(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating brand: %v", err)
+		return nil, err
+	}
+
+	return &models2.BrandOutput{
+		Brand: brand,
+	}, nil
+}
+
+func (u *UseCase) DeleteBrand(ctx context.Context, id int64) error {
+	if err := u.repo.DeleteBrand(ctx, id); err != nil {
+		u.logger.Errorf("Error deleting brand: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) RestoreBrand(ctx context.Context, id int64) (*models2.BrandOutput, error) {
+	if err := checkStaffRestore(ctx); err != nil {
+		return nil, err
+	}
+
+	brand, err := u.repo.RestoreBrand(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error restoring brand: %v", err)
+		return nil, err
+	}
+
+	return &models2.BrandOutput{
+		Brand: brand,
+	}, nil
+}
+
+func (u *UseCase) GetBrands(ctx context.Context, input *models2.GetBrandsInput) (*models2.GetBrandsOutput, error) {
+	if input.IncludeDeleted {
+		if err := checkStaffRead(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	brands, err := u.repo.GetBrands(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching brands: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetBrandsOutput{
+		Brands: brands,
+	}, nil
+}
+
+func (u *UseCase) GetBrandRollups(ctx context.Context, input *models2.GetBrandRollupsInput) (*models2.GetBrandRollupsOutput, error) {
+	if _, err := u.scopeProductFilter(ctx, &input.Filter); err != nil {
+		return nil, err
+	}
+
+	rollups, err := u.repo.GetBrandRollups(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching brand rollups: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetBrandRollupsOutput{
+		Rollups: rollups,
+	}, nil
+}
+
+// checkBrand rejects references to missing or deleted brands.
+func (u *UseCase) checkBrand(ctx context.Context, id int64) error {
+	if id == 0 {
+		return nil
+	}
+	if _, err := u.repo.GetBrand(ctx, &models2.GetBrandInput{ID: id}); err != nil {
+		u.logger.Errorf("Error fetching brand: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+)
+
+func (u *UseCase) SetBundle(ctx context.Context, input *models2.Bundle) (*models2.BundleOutput, error) {
+	if err := validateBundle(input); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.SetBundle(ctx, input); err != nil {
+		u.logger.Errorf("Error setting bundle: %v", err)
+		return nil, err
+	}
+
+	return &models2.BundleOutput{
+		Bundle: input,
+	}, nil
+}
+
+func (u *UseCase) DeleteBundle(ctx context.Context, productID int64) error {
+	err := u.repo.DeleteBundle(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error deleting bundle: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetBundle(ctx context.Context, productID int64) (*models2.BundleOutput, error) {
+	bundle, err := u.repo.GetBundle(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching bundle: %v", err)
+		return nil, err
+	}
+	if bundle == nil {
+		return nil, fmt.Errorf("product is not a bundle")
+	}
+
+	return &models2.BundleOutput{
+		Bundle: bundle,
+	}, nil
+}
+
+// bundleAvailability counts, per warehouse, how many complete bundles the
+// component stock makes up.
+func (u *UseCase) bundleAvailability(ctx context.Context, bundle *models2.Bundle) (*models2.GetProductAvailabilityOutput, error) {
+	var levels map[int64]*models2.StockLevel
+	var warehouses []int64
+	for i, component := range bundle.Components {
+		componentLevels, err := u.repo.GetProductAvailability(ctx, component.ProductID)
+		if err != nil {
+			u.logger.Errorf("Error fetching product availability: %v", err)
+			return nil, err
+		}
+
+		byWarehouse := make(map[int64]*models2.StockLevel, len(componentLevels))
+		for _, level := range componentLevels {
+			byWarehouse[level.WarehouseID] = &models2.StockLevel{
+				ProductID:     bundle.ProductID,
+				WarehouseID:   level.WarehouseID,
+				WarehouseCode: level.WarehouseCode,
+				OnHand:        level.OnHand / component.Quantity,
+				Available:     max(level.Available, 0) / component.Quantity,
+			}
+			if i == 0 {
+				warehouses = append(warehouses, level.WarehouseID)
+			}
+		}
+		if i == 0 {
+			levels = byWarehouse
+			continue
+		}
+		// A bundle is stocked only where every component is.
+		for id, level := range levels {
+			componentLevel, ok := byWarehouse[id]
+			if !ok {
+				delete(levels, id)
+				continue
+			}
+			level.OnHand = min(level.OnHand, componentLevel.OnHand)
+			level.Available = min(level.Available, componentLevel.Available)
+		}
+	}
+
+	output := &models2.GetProductAvailabilityOutput{}
+	for _, id := range warehouses {
+		level, ok := levels[id]
+		if !ok {
+			continue
+		}
+		level.Reserved = level.OnHand - level.Available
+		output.Levels = append(output.Levels, level)
+		output.OnHand += level.OnHand
+		output.Reserved += level.Reserved
+		output.Available += level.Available
+	}
+	return output, nil
+}
+
+func validateBundle(bundle *models2.Bundle) error {
+	switch bundle.Pricing {
+	case models2.BundlePricingFixed:
+		if bundle.DiscountPercent != 0 {
+			return fmt.Errorf("a discount applies only to bundles priced from their components")
+		}
+	case models2.BundlePricingComponents:
+		if bundle.DiscountPercent < 0 || bundle.DiscountPercent >= 100 {
+			return fmt.Errorf("bundle discount must be at least 0 and below 100 percent")
+		}
+	default:
+		return fmt.Errorf("unknown bundle pricing %q", bundle.Pricing)
+	}
+
+	if len(bundle.Components) == 0 {
+		return fmt.Errorf("bundle needs at least one component")
+	}
+	seen := make(map[int64]bool, len(bundle.Components))
+	for _, component := range bundle.Components {
+		if component.ProductID == bundle.ProductID {
+			return fmt.Errorf("bundle cannot contain itself")
+		}
+		if seen[component.ProductID] {
+			return fmt.Errorf("component product %d is listed twice", component.ProductID)
+		}
+		seen[component.ProductID] = true
+		if component.Quantity <= 0 {
+			return fmt.Errorf("quantity of component product %d must be positive", component.ProductID)
+		}
+	}
+	return nil
+}
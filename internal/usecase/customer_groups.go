@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"products/pkg/reqctx"
+	"strings"
+)
+
+func (u *UseCase) CreateCustomerGroup(ctx context.Context, input *models2.CustomerGroup) (*models2.CustomerGroupOutput, error) {
+	if err := validateCustomerGroup(input); err != nil {
+		return nil, err
+	}
+
+	group, err := u.repo.CreateCustomerGroup(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating customer group: %v", err)
+		return nil, err
+	}
+
+	return &models2.CustomerGroupOutput{
+		CustomerGroup: group,
+	}, nil
+}
+
+func (u *UseCase) UpdateCustomerGroup(ctx context.Context, input *models2.CustomerGroup) (*models2.CustomerGroupOutput, error) {
+	if err := validateCustomerGroup(input); err != nil {
+		return nil, err
+	}
+
+	group, err := u.repo.UpdateCustomerGroup(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating customer group: %v", err)
+		return nil, err
+	}
+
+	return &models2.CustomerGroupOutput{
+		CustomerGroup: group,
+	}, nil
+}
+
+func (u *UseCase) GetCustomerGroups(ctx context.Context) (*models2.GetCustomerGroupsOutput, error) {
+	groups, err := u.repo.GetCustomerGroups(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching customer groups: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetCustomerGroupsOutput{
+		CustomerGroups: groups,
+	}, nil
+}
+
+func (u *UseCase) SetProductVisibility(ctx context.Context, input *models2.SetProductVisibilityInput) error {
+	if !input.Restricted && len(input.GroupIDs) > 0 {
+		return fmt.Errorf("customer groups are only listed for a restricted product")
+	}
+
+	err := u.repo.SetProductVisibility(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting product visibility: %v", err)
+		return err
+	}
+	return nil
+}
+
+// customerGroup returns the caller's customer group, or nil for callers
+// outside any group.
+func (u *UseCase) customerGroup(ctx context.Context) (*models2.CustomerGroup, error) {
+	code := reqctx.CustomerGroup(ctx)
+	if code == "" {
+		return nil, nil
+	}
+
+	group, err := u.repo.GetCustomerGroup(ctx, code)
+	if err != nil {
+		u.logger.Errorf("Error fetching customer group: %v", err)
+		return nil, err
+	}
+	return group, nil
+}
+
+// scopeProductFilter narrows a product filter to what the caller's customer
+// group may see and prices it in the group's price list.
+func (u *UseCase) scopeProductFilter(ctx context.Context, filter *models2.ProductFilter) (*models2.CustomerGroup, error) {
+	group, err := u.customerGroup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter.BaseCurrency = u.cfg.Pricing.BaseCurrency
+	scopeVisibility(ctx, group, filter)
+	if group != nil {
+		filter.PriceListID = group.PriceListID
+	}
+	return group, nil
+}
+
+// scopeVisibility limits a product filter to the products the customer group
+// sees. Staff outside any group see restricted products too.
+func scopeVisibility(ctx context.Context, group *models2.CustomerGroup, filter *models2.ProductFilter) {
+	if group != nil {
+		filter.CustomerGroupID = group.ID
+		return
+	}
+	filter.IncludeRestricted = isStaff(ctx)
+}
+
+// customerGroupPriceList checks the price list and currency a caller asked
+// for against its customer group and returns the code of the list to price
+// in. Staff may ask for any list. Other callers price in their group's list:
+// an explicit list must be that list and a currency must be its currency.
+// Callers whose group has no list see base prices, which a currency converts
+// through the public price lists.
+func (u *UseCase) customerGroupPriceList(ctx context.Context, group *models2.CustomerGroup, priceList, currency string) (string, error) {
+	var own *models2.PriceList
+	if group != nil && group.PriceListID != 0 {
+		var err error
+		if own, err = u.repo.GetPriceListByID(ctx, group.PriceListID); err != nil {
+			u.logger.Errorf("Error fetching customer group price list: %v", err)
+			return "", err
+		}
+	}
+
+	if isStaff(ctx) {
+		if priceList == "" && own != nil {
+			return own.Code, nil
+		}
+		return priceList, nil
+	}
+	if priceList != "" && (own == nil || priceList != own.Code) {
+		return "", fmt.Errorf("price list %q is not available to the caller", priceList)
+	}
+	if own == nil {
+		return "", nil
+	}
+	if currency != "" && !strings.EqualFold(currency, own.Currency) {
+		return "", fmt.Errorf("currency %q is not available to the customer group", currency)
+	}
+	return own.Code, nil
+}
+
+// publicPriceList returns the first price list of a currency that no customer
+// group prices in, or nil when there is none.
+func (u *UseCase) publicPriceList(ctx context.Context, currency string) (*models2.PriceList, error) {
+	groups, err := u.repo.GetCustomerGroups(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching customer groups: %v", err)
+		return nil, err
+	}
+	assigned := make(map[int64]bool, len(groups))
+	for _, group := range groups {
+		assigned[group.PriceListID] = true
+	}
+
+	priceLists, err := u.repo.GetPriceLists(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching price lists: %v", err)
+		return nil, err
+	}
+	for _, priceList := range priceLists {
+		if priceList.Currency == currency && !assigned[priceList.ID] {
+			return priceList, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkProductVisible hides restricted products from callers outside their
+// customer groups as if they did not exist, following scopeVisibility.
+func (u *UseCase) checkProductVisible(ctx context.Context, group *models2.CustomerGroup, productID int64) error {
+	var groupID int64
+	if group != nil {
+		groupID = group.ID
+	} else if isStaff(ctx) {
+		return nil
+	}
+
+	visible, err := u.repo.IsProductVisible(ctx, groupID, productID)
+	if err != nil {
+		u.logger.Errorf("Error checking product visibility: %v", err)
+		return err
+	}
+	if !visible {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+func validateCustomerGroup(group *models2.CustomerGroup) error {
+	group.Code = strings.TrimSpace(group.Code)
+	if group.Code == "" {
+		return fmt.Errorf("customer group code must not be empty")
+	}
+	if group.Name == "" {
+		group.Name = group.Code
+	}
+	return nil
+}
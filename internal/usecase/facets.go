@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+)
+
+const defaultPriceBucketSize = 100
+
+func (u *UseCase) GetProductFacets(ctx context.Context, input *models2.GetProductFacetsInput) (*models2.GetProductFacetsOutput, error) {
+	if input.PriceBucketSize < 0 {
+		return nil, fmt.Errorf("price bucket size must be positive")
+	}
+	if input.PriceBucketSize == 0 {
+		input.PriceBucketSize = defaultPriceBucketSize
+	}
+	if err := checkProductFilterRead(ctx, &input.Filter); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.scopeProductFilter(ctx, &input.Filter); err != nil {
+		return nil, err
+	}
+	if input.Filter.Locales == nil {
+		input.Filter.Locales = u.locales(ctx)
+	}
+
+	facets, err := u.repo.GetProductFacets(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching product facets: %v", err)
+		return nil, err
+	}
+	return facets, nil
+}
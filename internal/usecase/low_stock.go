@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+)
+
+const (
+	defaultLowStockLimit = 50
+	maxLowStockLimit     = 500
+)
+
+func (u *UseCase) SetProductReorderThreshold(ctx context.Context, input *models2.SetReorderThresholdInput) error {
+	if input.Threshold != nil && *input.Threshold < 0 {
+		return fmt.Errorf("reorder threshold must not be negative")
+	}
+
+	err := u.repo.SetProductReorderThreshold(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting product reorder threshold: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) SetCategoryReorderThreshold(ctx context.Context, input *models2.SetReorderThresholdInput) error {
+	if input.Threshold != nil && *input.Threshold < 0 {
+		return fmt.Errorf("reorder threshold must not be negative")
+	}
+
+	err := u.repo.SetCategoryReorderThreshold(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting category reorder threshold: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) ListLowStockProducts(ctx context.Context, input *models2.ListLowStockProductsInput) (*models2.ListLowStockProductsOutput, error) {
+	if input.Limit <= 0 {
+		input.Limit = defaultLowStockLimit
+	}
+	if input.Limit > maxLowStockLimit {
+		input.Limit = maxLowStockLimit
+	}
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	items, total, err := u.repo.ListLowStockProducts(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching low stock products: %v", err)
+		return nil, err
+	}
+
+	return &models2.ListLowStockProductsOutput{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+func (u *UseCase) EvaluateLowStock(ctx context.Context) (*models2.EvaluateLowStockOutput, error) {
+	crossed, err := u.repo.EvaluateLowStock(ctx)
+	if err != nil {
+		u.logger.Errorf("Error evaluating low stock: %v", err)
+		return nil, err
+	}
+
+	return &models2.EvaluateLowStockOutput{
+		Crossed: crossed,
+	}, nil
+}
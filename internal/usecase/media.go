@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/net/context"
+	"net/url"
+	models2 "products/internal/models"
+)
+
+// ThumbnailGenerator produces the thumbnail of the image at a URL.
+type ThumbnailGenerator interface {
+	Generate(ctx context.Context, url string) ([]byte, error)
+}
+
+// AddProductMedia registers an image in the product's gallery together with
+// its thumbnail. Without a configured thumbnail service the image is stored
+// without one.
+func (u *UseCase) AddProductMedia(ctx context.Context, input *models2.AddProductMediaInput) (*models2.MediaOutput, error) {
+	if err := validateMediaURL(input.URL); err != nil {
+		return nil, err
+	}
+
+	var err error
+	input.Thumbnail, input.ThumbnailRef, err = u.generateThumbnail(ctx, input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := u.repo.AddProductMedia(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error adding product media: %v", err)
+		return nil, err
+	}
+
+	return &models2.MediaOutput{
+		Media: media,
+	}, nil
+}
+
+func (u *UseCase) GetProductMedia(ctx context.Context, productID int64) (*models2.GetProductMediaOutput, error) {
+	media, err := u.repo.GetProductMedia(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product media: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductMediaOutput{
+		Media: media,
+	}, nil
+}
+
+func (u *UseCase) SetPrimaryMedia(ctx context.Context, id int64) error {
+	if err := u.repo.SetPrimaryMedia(ctx, id); err != nil {
+		u.logger.Errorf("Error setting primary media: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) ReorderProductMedia(ctx context.Context, input *models2.ReorderProductMediaInput) error {
+	seen := make(map[int64]bool, len(input.MediaIDs))
+	for _, id := range input.MediaIDs {
+		if seen[id] {
+			return fmt.Errorf("media %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	if err := u.repo.ReorderProductMedia(ctx, input); err != nil {
+		u.logger.Errorf("Error reordering product media: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) DeleteProductMedia(ctx context.Context, id int64) error {
+	if err := u.repo.DeleteProductMedia(ctx, id); err != nil {
+		u.logger.Errorf("Error deleting product media: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RegenerateMediaThumbnail asks the thumbnail service again, e.g. for media
+// registered while the service was not configured.
+func (u *UseCase) RegenerateMediaThumbnail(ctx context.Context, id int64) (*models2.MediaOutput, error) {
+	if u.thumbnails == nil {
+		return nil, fmt.Errorf("thumbnail service is not configured")
+	}
+
+	media, err := u.repo.GetMedia(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error fetching media: %v", err)
+		return nil, err
+	}
+
+	thumbnail, ref, err := u.generateThumbnail(ctx, media.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.repo.SetMediaThumbnail(ctx, id, ref, thumbnail); err != nil {
+		u.logger.Errorf("Error setting media thumbnail: %v", err)
+		return nil, err
+	}
+	media.ThumbnailRef = ref
+
+	return &models2.MediaOutput{
+		Media: media,
+	}, nil
+}
+
+func (u *UseCase) GetThumbnail(ctx context.Context, ref string) ([]byte, error) {
+	thumbnail, err := u.repo.GetThumbnail(ctx, ref)
+	if err != nil {
+		u.logger.Errorf("Error fetching thumbnail: %v", err)
+		return nil, err
+	}
+	return thumbnail, nil
+}
+
+// generateThumbnail returns the thumbnail of the image and its content
+// reference, or nothing when no thumbnail service is configured.
+func (u *UseCase) generateThumbnail(ctx context.Context, imageURL string) ([]byte, string, error) {
+	if u.thumbnails == nil {
+		return nil, "", nil
+	}
+
+	thumbnail, err := u.thumbnails.Generate(ctx, imageURL)
+	if err != nil {
+		u.logger.Errorf("Error generating thumbnail for %s: %v", imageURL, err)
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(thumbnail)
+	return thumbnail, hex.EncodeToString(sum[:]), nil
+}
+
+func validateMediaURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("media url must be an absolute http(s) url")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"regexp"
+	"strings"
+)
+
+// maxPriceListDepth bounds the chain of base price lists followed for a price.
+const maxPriceListDepth = 10
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func (u *UseCase) CreatePriceList(ctx context.Context, input *models2.PriceList) (*models2.PriceListOutput, error) {
+	if err := u.validatePriceList(ctx, input); err != nil {
+		return nil, err
+	}
+
+	priceList, err := u.repo.CreatePriceList(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating price list: %v", err)
+		return nil, err
+	}
+
+	return &models2.PriceListOutput{
+		PriceList: priceList,
+	}, nil
+}
+
+func (u *UseCase) UpdatePriceList(ctx context.Context, input *models2.PriceList) (*models2.PriceListOutput, error) {
+	if err := u.validatePriceList(ctx, input); err != nil {
+		return nil, err
+	}
+
+	priceList, err := u.repo.UpdatePriceList(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating price list: %v", err)
+		return nil, err
+	}
+
+	return &models2.PriceListOutput{
+		PriceList: priceList,
+	}, nil
+}
+
+func (u *UseCase) GetPriceLists(ctx context.Context) (*models2.GetPriceListsOutput, error) {
+	priceLists, err := u.repo.GetPriceLists(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching price lists: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetPriceListsOutput{
+		PriceLists: priceLists,
+	}, nil
+}
+
+func (u *UseCase) SetPriceListPrice(ctx context.Context, input *models2.SetPriceListPriceInput) error {
+	if input.Price != nil && *input.Price < 0 {
+		return fmt.Errorf("price must not be negative")
+	}
+
+	err := u.repo.SetPriceListPrice(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting price list price: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) SetExchangeRate(ctx context.Context, input *models2.ExchangeRate) error {
+	input.BaseCurrency = strings.ToUpper(input.BaseCurrency)
+	input.QuoteCurrency = strings.ToUpper(input.QuoteCurrency)
+	if !currencyPattern.MatchString(input.BaseCurrency) || !currencyPattern.MatchString(input.QuoteCurrency) {
+		return fmt.Errorf("currencies must be ISO 4217 codes")
+	}
+	if input.BaseCurrency == input.QuoteCurrency {
+		return fmt.Errorf("exchange rate needs two different currencies")
+	}
+	if input.Rate <= 0 {
+		return fmt.Errorf("exchange rate must be positive")
+	}
+
+	err := u.repo.SetExchangeRate(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting exchange rate: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetProductPrice resolves the price of a product in a price list, or in the
+// first public price list of a currency. Without either it returns the base
+// price, converted when another currency is asked for. The tier covering the
+// quantity replaces the base price, and a price the list or one of its base
+// lists sets for the product takes precedence over tiers. The product_price
+// database function does the arithmetic, so the price matches the one
+// listings and price filters use.
+func (u *UseCase) GetProductPrice(ctx context.Context, input *models2.GetProductPriceInput) (*models2.ProductPrice, error) {
+	output := &models2.ProductPrice{ProductID: input.ProductID, Currency: u.cfg.Pricing.BaseCurrency}
+	currency := strings.ToUpper(input.Currency)
+
+	var priceList *models2.PriceList
+	var err error
+	switch {
+	case input.PriceList != "":
+		priceList, err = u.repo.GetPriceList(ctx, input.PriceList)
+		if err != nil {
+			u.logger.Errorf("Error fetching price list: %v", err)
+			return nil, err
+		}
+	case currency != "" && currency != output.Currency:
+		priceList, err = u.publicPriceList(ctx, currency)
+		if err != nil {
+			return nil, err
+		}
+		output.Currency = currency
+	}
+
+	var priceListID int64
+	if priceList != nil {
+		priceListID = priceList.ID
+		output.Currency, output.PriceListCode = priceList.Currency, priceList.Code
+	}
+	output.Price, err = u.repo.GetProductPrice(ctx, input.ProductID, priceListID, max(input.Quantity, 1), u.cfg.Pricing.BaseCurrency, output.Currency)
+	if err != nil {
+		u.logger.Errorf("Error fetching product price: %v", err)
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (u *UseCase) validatePriceList(ctx context.Context, priceList *models2.PriceList) error {
+	priceList.Code = strings.TrimSpace(priceList.Code)
+	if priceList.Code == "" {
+		return fmt.Errorf("price list code must not be empty")
+	}
+	if priceList.Name == "" {
+		priceList.Name = priceList.Code
+	}
+	priceList.Currency = strings.ToUpper(priceList.Currency)
+	if !currencyPattern.MatchString(priceList.Currency) {
+		return fmt.Errorf("currency must be an ISO 4217 code")
+	}
+
+	rule := &priceList.Rounding
+	if rule.Mode == "" {
+		rule.Mode = models2.RoundingModeNone
+	}
+	if rule.Increment == 0 {
+		rule.Increment = 0.01
+	}
+	switch rule.Mode {
+	case models2.RoundingModeNone, models2.RoundingModeNearest, models2.RoundingModeUp, models2.RoundingModeDown:
+	default:
+		return fmt.Errorf("unknown rounding mode %q", rule.Mode)
+	}
+	if rule.Increment < 0 {
+		return fmt.Errorf("rounding increment must be positive")
+	}
+	if rule.Ending != 0 {
+		if rule.Ending < 0 || rule.Ending >= 1 {
+			return fmt.Errorf("price ending must be between 0 and 1")
+		}
+		if rule.Mode == models2.RoundingModeNone || rule.Increment < 1 {
+			return fmt.Errorf("price ending needs a rounding mode with an increment of at least 1")
+		}
+	}
+
+	// Walk the base chain to reject cycles through this list.
+	for id, depth := priceList.BasePriceListID, 0; id != 0; depth++ {
+		if id == priceList.ID || depth > maxPriceListDepth {
+			return fmt.Errorf("base price lists must not form a cycle")
+		}
+		base, err := u.repo.GetPriceListByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		id = base.BasePriceListID
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"products/config"
+	repository "products/internal"
+	models2 "products/internal/models"
+	"products/pkg/logger"
+	"products/pkg/reqctx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// priceListRepository fakes the price lists and customer groups of the
+// repository; any other repository method panics.
+type priceListRepository struct {
+	repository.Postgres
+
+	priceLists []*models2.PriceList
+	groups     []*models2.CustomerGroup
+	// prices are the own prices of the lists by list ID.
+	prices    map[int64]float64
+	tiers     []*models2.PriceTier
+	basePrice float64
+}
+
+func (r *priceListRepository) GetPriceListByID(_ context.Context, id int64) (*models2.PriceList, error) {
+	for _, priceList := range r.priceLists {
+		if priceList.ID == id {
+			return priceList, nil
+		}
+	}
+	return nil, fmt.Errorf("price list not found")
+}
+
+func (r *priceListRepository) GetPriceLists(context.Context) ([]*models2.PriceList, error) {
+	return r.priceLists, nil
+}
+
+func (r *priceListRepository) GetCustomerGroups(context.Context) ([]*models2.CustomerGroup, error) {
+	return r.groups, nil
+}
+
+func (r *priceListRepository) GetPriceList(_ context.Context, code string) (*models2.PriceList, error) {
+	for _, priceList := range r.priceLists {
+		if priceList.Code == code {
+			return priceList, nil
+		}
+	}
+	return nil, fmt.Errorf("price list %q not found", code)
+}
+
+func (r *priceListRepository) GetPriceListPrice(_ context.Context, priceListID, _ int64) (*float64, error) {
+	price, ok := r.prices[priceListID]
+	if !ok {
+		return nil, nil
+	}
+	return &price, nil
+}
+
+func (r *priceListRepository) GetPriceTier(_ context.Context, _, quantity int64) (*models2.PriceTier, error) {
+	for _, tier := range r.tiers {
+		if tier.MinQuantity <= quantity && (tier.MaxQuantity == 0 || tier.MaxQuantity >= quantity) {
+			return tier, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *priceListRepository) SetPriceTiers(_ context.Context, input *models2.SetPriceTiersInput) error {
+	r.tiers = input.Tiers
+	return nil
+}
+
+// GetProductPrice follows product_price: the list's own price, else the tier
+// covering the quantity, else the base price.
+func (r *priceListRepository) GetProductPrice(ctx context.Context, productID, priceListID, quantity int64, _, _ string) (float64, error) {
+	if price, ok := r.prices[priceListID]; ok {
+		return price, nil
+	}
+	tier, _ := r.GetPriceTier(ctx, productID, quantity)
+	if tier != nil {
+		return tier.UnitPrice, nil
+	}
+	return r.basePrice, nil
+}
+
+func newPriceListUseCase(t *testing.T) (*UseCase, *priceListRepository) {
+	t.Helper()
+
+	repo := &priceListRepository{
+		priceLists: []*models2.PriceList{
+			{ID: 1, Code: "wholesale-usd", Currency: "USD"},
+			{ID: 2, Code: "retail-usd", Currency: "USD"},
+			{ID: 3, Code: "retail-eur", Currency: "EUR"},
+			{ID: 4, Code: "outlet-usd", Currency: "USD"},
+		},
+		groups: []*models2.CustomerGroup{
+			{ID: 10, Code: "wholesale", PriceListID: 1},
+			{ID: 11, Code: "members"},
+		},
+		prices:    map[int64]float64{1: 70, 2: 95, 3: 90},
+		basePrice: 8000,
+	}
+	log := logger.NewApiLogger(&config.Config{})
+	require.NoError(t, log.InitLogger())
+	cfg := &config.Config{}
+	cfg.Pricing.BaseCurrency = "RUB"
+	return NewUseCase(cfg, repo, nil, log), repo
+}
+
+func TestCustomerGroupPriceList(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      string
+		group     int
+		priceList string
+		currency  string
+		want      string
+		wantErr   bool
+	}{
+		{name: "anonymous sees base prices", group: -1},
+		{name: "anonymous currency", group: -1, currency: "USD"},
+		{name: "anonymous explicit list", group: -1, priceList: "wholesale-usd", wantErr: true},
+		{name: "group list", group: 0, want: "wholesale-usd"},
+		{name: "group list named", group: 0, priceList: "wholesale-usd", want: "wholesale-usd"},
+		{name: "group list currency", group: 0, currency: "usd", want: "wholesale-usd"},
+		{name: "group other list", group: 0, priceList: "retail-usd", wantErr: true},
+		{name: "group other currency", group: 0, currency: "EUR", wantErr: true},
+		{name: "group without list", group: 1, currency: "EUR"},
+		{name: "group without list explicit list", group: 1, priceList: "wholesale-usd", wantErr: true},
+		{name: "staff any list", role: models2.ActorRoleEditor, group: -1, priceList: "wholesale-usd", want: "wholesale-usd"},
+		{name: "staff in group", role: models2.ActorRoleEditor, group: 0, want: "wholesale-usd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, repo := newPriceListUseCase(t)
+			var group *models2.CustomerGroup
+			if tt.group >= 0 {
+				group = repo.groups[tt.group]
+			}
+			ctx := reqctx.WithRole(context.Background(), tt.role)
+
+			got, err := u.customerGroupPriceList(ctx, group, tt.priceList, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetProductPriceCurrencySkipsGroupLists(t *testing.T) {
+	u, _ := newPriceListUseCase(t)
+
+	price, err := u.GetProductPrice(context.Background(), &models2.GetProductPriceInput{ProductID: 1, Currency: "usd"})
+	require.NoError(t, err)
+	assert.Equal(t, "retail-usd", price.PriceListCode)
+	assert.Equal(t, 95.0, price.Price)
+}
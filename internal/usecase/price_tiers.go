@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"sort"
+)
+
+// SetPriceTiers replaces a product's tiers. Tiers must not overlap, and only
+// the last tier may be open-ended.
+func (u *UseCase) SetPriceTiers(ctx context.Context, input *models2.SetPriceTiersInput) (*models2.GetPriceTiersOutput, error) {
+	tiers := make([]*models2.PriceTier, len(input.Tiers))
+	copy(tiers, input.Tiers)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinQuantity < tiers[j].MinQuantity })
+
+	for i, tier := range tiers {
+		if tier.MinQuantity <= 0 {
+			return nil, fmt.Errorf("tier minimum quantity must be positive")
+		}
+		if tier.MaxQuantity != 0 && tier.MaxQuantity < tier.MinQuantity {
+			return nil, fmt.Errorf("tier %d-%d ends before it starts", tier.MinQuantity, tier.MaxQuantity)
+		}
+		if tier.UnitPrice < 0 {
+			return nil, fmt.Errorf("tier unit price must not be negative")
+		}
+		if i > 0 {
+			prev := tiers[i-1]
+			if prev.MaxQuantity == 0 || prev.MaxQuantity >= tier.MinQuantity {
+				return nil, fmt.Errorf("tier starting at %d overlaps the tier starting at %d", tier.MinQuantity, prev.MinQuantity)
+			}
+		}
+	}
+	input.Tiers = tiers
+
+	if err := u.repo.SetPriceTiers(ctx, input); err != nil {
+		u.logger.Errorf("Error setting price tiers: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetPriceTiersOutput{
+		Tiers: tiers,
+	}, nil
+}
+
+func (u *UseCase) GetPriceTiers(ctx context.Context, productID int64) (*models2.GetPriceTiersOutput, error) {
+	tiers, err := u.repo.GetPriceTiers(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching price tiers: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetPriceTiersOutput{
+		Tiers: tiers,
+	}, nil
+}
+
+// GetQuantityPrice returns the unit price and line total for a quantity,
+// resolved by GetProductPrice.
+func (u *UseCase) GetQuantityPrice(ctx context.Context, input *models2.GetQuantityPriceInput) (*models2.QuantityPrice, error) {
+	if input.Quantity <= 0 {
+		return nil, fmt.Errorf("quantity for product %d must be positive", input.ProductID)
+	}
+
+	price, err := u.GetProductPrice(ctx, &models2.GetProductPriceInput{
+		ProductID: input.ProductID,
+		PriceList: input.PriceList,
+		Currency:  input.Currency,
+		Quantity:  input.Quantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := &models2.QuantityPrice{
+		ProductID: input.ProductID,
+		Quantity:  input.Quantity,
+		UnitPrice: price.Price,
+		LineTotal: roundCents(price.Price * float64(input.Quantity)),
+		Currency:  price.Currency,
+	}
+
+	agreed := false
+	if price.PriceListCode != "" {
+		priceList, err := u.repo.GetPriceList(ctx, price.PriceListCode)
+		if err != nil {
+			u.logger.Errorf("Error fetching price list: %v", err)
+			return nil, err
+		}
+		if agreed, err = u.hasListPrice(ctx, priceList, input.ProductID); err != nil {
+			return nil, err
+		}
+	}
+	if !agreed {
+		output.Tier, err = u.repo.GetPriceTier(ctx, input.ProductID, input.Quantity)
+		if err != nil {
+			u.logger.Errorf("Error fetching price tier: %v", err)
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// hasListPrice reports whether the price list or one of its base lists sets
+// its own price for the product.
+func (u *UseCase) hasListPrice(ctx context.Context, priceList *models2.PriceList, productID int64) (bool, error) {
+	for depth := 0; ; depth++ {
+		if depth > maxPriceListDepth {
+			return false, fmt.Errorf("price list %q has too many base lists", priceList.Code)
+		}
+		own, err := u.repo.GetPriceListPrice(ctx, priceList.ID, productID)
+		if err != nil {
+			u.logger.Errorf("Error fetching price list price: %v", err)
+			return false, err
+		}
+		if own != nil {
+			return true, nil
+		}
+		if priceList.BasePriceListID == 0 {
+			return false, nil
+		}
+		if priceList, err = u.repo.GetPriceListByID(ctx, priceList.BasePriceListID); err != nil {
+			u.logger.Errorf("Error fetching base price list: %v", err)
+			return false, err
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPriceTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		tiers   []*models2.PriceTier
+		want    []int64
+		wantErr bool
+	}{
+		{name: "no tiers"},
+		{
+			name:  "sorted by minimum quantity",
+			tiers: []*models2.PriceTier{{MinQuantity: 10, UnitPrice: 80}, {MinQuantity: 1, MaxQuantity: 9, UnitPrice: 100}},
+			want:  []int64{1, 10},
+		},
+		{
+			name:  "gap between tiers",
+			tiers: []*models2.PriceTier{{MinQuantity: 1, MaxQuantity: 4, UnitPrice: 100}, {MinQuantity: 10, MaxQuantity: 20, UnitPrice: 80}},
+			want:  []int64{1, 10},
+		},
+		{
+			name:    "overlapping tiers",
+			tiers:   []*models2.PriceTier{{MinQuantity: 1, MaxQuantity: 10, UnitPrice: 100}, {MinQuantity: 10, UnitPrice: 80}},
+			wantErr: true,
+		},
+		{
+			name:    "open-ended tier not last",
+			tiers:   []*models2.PriceTier{{MinQuantity: 1, UnitPrice: 100}, {MinQuantity: 10, UnitPrice: 80}},
+			wantErr: true,
+		},
+		{
+			name:    "tier ends before it starts",
+			tiers:   []*models2.PriceTier{{MinQuantity: 5, MaxQuantity: 3, UnitPrice: 100}},
+			wantErr: true,
+		},
+		{
+			name:    "zero minimum quantity",
+			tiers:   []*models2.PriceTier{{MinQuantity: 0, MaxQuantity: 9, UnitPrice: 100}},
+			wantErr: true,
+		},
+		{
+			name:    "negative unit price",
+			tiers:   []*models2.PriceTier{{MinQuantity: 1, UnitPrice: -1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, repo := newPriceListUseCase(t)
+
+			output, err := u.SetPriceTiers(context.Background(), &models2.SetPriceTiersInput{ProductID: 1, Tiers: tt.tiers})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, repo.tiers)
+				return
+			}
+			require.NoError(t, err)
+			var got []int64
+			for _, tier := range output.Tiers {
+				got = append(got, tier.MinQuantity)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, output.Tiers, repo.tiers)
+		})
+	}
+}
+
+func TestGetQuantityPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  int64
+		priceList string
+		unitPrice float64
+		lineTotal float64
+		currency  string
+		// tier is the minimum quantity of the tier reported, zero for none.
+		tier    int64
+		wantErr bool
+	}{
+		{name: "zero quantity", quantity: 0, wantErr: true},
+		{name: "first tier", quantity: 5, unitPrice: 100, lineTotal: 500, currency: "RUB", tier: 2},
+		{name: "open-ended tier", quantity: 12, unitPrice: 80, lineTotal: 960, currency: "RUB", tier: 10},
+		{name: "below the tiers", quantity: 1, unitPrice: 120, lineTotal: 120, currency: "RUB"},
+		{name: "list price over tiers", quantity: 12, priceList: "retail-usd", unitPrice: 95, lineTotal: 1140, currency: "USD"},
+		{name: "tier through a list without a price", quantity: 12, priceList: "outlet-usd", unitPrice: 80, lineTotal: 960, currency: "USD", tier: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, repo := newPriceListUseCase(t)
+			repo.basePrice = 120
+			repo.tiers = []*models2.PriceTier{{MinQuantity: 2, MaxQuantity: 9, UnitPrice: 100}, {MinQuantity: 10, UnitPrice: 80}}
+
+			price, err := u.GetQuantityPrice(context.Background(), &models2.GetQuantityPriceInput{
+				ProductID: 1,
+				Quantity:  tt.quantity,
+				PriceList: tt.priceList,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.unitPrice, price.UnitPrice)
+			assert.Equal(t, tt.lineTotal, price.LineTotal)
+			assert.Equal(t, tt.currency, price.Currency)
+			if tt.tier == 0 {
+				assert.Nil(t, price.Tier)
+			} else {
+				require.NotNil(t, price.Tier)
+				assert.Equal(t, tt.tier, price.Tier.MinQuantity)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"math"
+	models2 "products/internal/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (u *UseCase) CreatePromotion(ctx context.Context, input *models2.Promotion) (*models2.PromotionOutput, error) {
+	if err := validatePromotion(input); err != nil {
+		return nil, err
+	}
+
+	promotion, err := u.repo.CreatePromotion(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating promotion: %v", err)
+		return nil, err
+	}
+
+	return &models2.PromotionOutput{
+		Promotion: promotion,
+	}, nil
+}
+
+func (u *UseCase) UpdatePromotion(ctx context.Context, input *models2.Promotion) (*models2.PromotionOutput, error) {
+	if err := validatePromotion(input); err != nil {
+		return nil, err
+	}
+
+	promotion, err := u.repo.UpdatePromotion(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating promotion: %v", err)
+		return nil, err
+	}
+
+	return &models2.PromotionOutput{
+		Promotion: promotion,
+	}, nil
+}
+
+func (u *UseCase) DeletePromotion(ctx context.Context, id int64) error {
+	err := u.repo.DeletePromotion(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error deleting promotion: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetPromotions(ctx context.Context) (*models2.GetPromotionsOutput, error) {
+	promotions, err := u.repo.GetPromotions(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching promotions: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetPromotionsOutput{
+		Promotions: promotions,
+	}, nil
+}
+
+// QuotePrices prices every item, including its quantity tier, and applies the running promotions to it in
+// priority order.
+func (u *UseCase) QuotePrices(ctx context.Context, input *models2.QuotePricesInput) (*models2.QuotePricesOutput, error) {
+	if len(input.Items) == 0 {
+		return nil, fmt.Errorf("quote must contain at least one item")
+	}
+
+	group, err := u.customerGroup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if input.PriceList, err = u.customerGroupPriceList(ctx, group, input.PriceList, input.Currency); err != nil {
+		return nil, err
+	}
+
+	promotions, err := u.repo.GetActivePromotions(ctx, time.Now())
+	if err != nil {
+		u.logger.Errorf("Error fetching promotions: %v", err)
+		return nil, err
+	}
+
+	output := &models2.QuotePricesOutput{}
+	// rates caches the conversion of promotion amounts into the quote currency.
+	rates := make(map[string]float64)
+	for _, item := range input.Items {
+		if err := u.checkProductVisible(ctx, group, item.ProductID); err != nil {
+			return nil, err
+		}
+		product, err := u.repo.GetProduct(ctx, &models2.GetProductInput{ID: item.ProductID})
+		if err != nil {
+			u.logger.Errorf("Error fetching product: %v", err)
+			return nil, err
+		}
+		price, err := u.GetQuantityPrice(ctx, &models2.GetQuantityPriceInput{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			PriceList: input.PriceList,
+			Currency:  input.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+		output.Currency = price.Currency
+
+		line := &models2.QuoteLine{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: price.UnitPrice,
+			Subtotal:  price.LineTotal,
+		}
+		line.Total = line.Subtotal
+
+		for _, promotion := range promotions {
+			if promotion.Stacking == models2.PromotionStackingExclusive && len(line.Promotions) > 0 {
+				continue
+			}
+			if !promotionMatches(promotion, item.ProductID, product.CategoryId) {
+				continue
+			}
+
+			rate, err := u.promotionRate(ctx, promotion, price.Currency, rates)
+			if err != nil {
+				return nil, err
+			}
+			if promotion.MinPrice != nil && price.UnitPrice < *promotion.MinPrice*rate {
+				continue
+			}
+
+			discount := roundCents(math.Min(promotionDiscount(promotion, line, rate), line.Total))
+			if discount <= 0 {
+				continue
+			}
+			line.Total = roundCents(line.Total - discount)
+			line.Discount = roundCents(line.Discount + discount)
+			line.Promotions = append(line.Promotions, &models2.AppliedPromotion{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Discount:    discount,
+			})
+
+			if promotion.Stacking == models2.PromotionStackingExclusive {
+				break
+			}
+		}
+
+		output.Lines = append(output.Lines, line)
+		output.Total = roundCents(output.Total + line.Total)
+	}
+
+	if input.Region != "" {
+		taxInput := &models2.ComputeTaxInput{Region: input.Region}
+		for _, line := range output.Lines {
+			taxInput.Lines = append(taxInput.Lines, &models2.TaxableLine{ProductID: line.ProductID, Amount: line.Total})
+		}
+		if output.Tax, err = u.ComputeTax(ctx, taxInput); err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+// promotionRate converts amounts in the promotion's currency into currency.
+func (u *UseCase) promotionRate(ctx context.Context, promotion *models2.Promotion, currency string, rates map[string]float64) (float64, error) {
+	from := promotion.Currency
+	if from == "" {
+		from = u.cfg.Pricing.BaseCurrency
+	}
+	if from == currency {
+		return 1, nil
+	}
+	if rate, ok := rates[from]; ok {
+		return rate, nil
+	}
+
+	rate, err := u.repo.GetExchangeRate(ctx, from, currency)
+	if err != nil {
+		u.logger.Errorf("Error fetching exchange rate: %v", err)
+		return 0, err
+	}
+	rates[from] = rate
+	return rate, nil
+}
+
+func promotionMatches(promotion *models2.Promotion, productID, categoryID int64) bool {
+	if len(promotion.ProductIDs) > 0 && !slices.Contains(promotion.ProductIDs, productID) {
+		return false
+	}
+	if len(promotion.CategoryIDs) > 0 && !slices.Contains(promotion.CategoryIDs, categoryID) {
+		return false
+	}
+	return true
+}
+
+// promotionDiscount is the discount on the line's current total.
+func promotionDiscount(promotion *models2.Promotion, line *models2.QuoteLine, rate float64) float64 {
+	switch promotion.Kind {
+	case models2.PromotionKindPercent:
+		return line.Total * promotion.Value / 100
+	case models2.PromotionKindFixed:
+		return promotion.Value * rate * float64(line.Quantity)
+	case models2.PromotionKindBuyXGetY:
+		free := line.Quantity / (promotion.BuyQuantity + promotion.GetQuantity) * promotion.GetQuantity
+		return line.Total / float64(line.Quantity) * float64(free)
+	}
+	return 0
+}
+
+func validatePromotion(promotion *models2.Promotion) error {
+	if strings.TrimSpace(promotion.Name) == "" {
+		return fmt.Errorf("promotion name must not be empty")
+	}
+	if promotion.Value < 0 {
+		return fmt.Errorf("promotion value must not be negative")
+	}
+
+	switch promotion.Kind {
+	case models2.PromotionKindPercent:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return fmt.Errorf("percent promotion value must be between 0 and 100")
+		}
+	case models2.PromotionKindFixed:
+		if promotion.Value <= 0 {
+			return fmt.Errorf("fixed promotion value must be positive")
+		}
+	case models2.PromotionKindBuyXGetY:
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return fmt.Errorf("buy x get y promotion needs positive buy and get quantities")
+		}
+	default:
+		return fmt.Errorf("unknown promotion kind %q", promotion.Kind)
+	}
+
+	if promotion.Stacking == "" {
+		promotion.Stacking = models2.PromotionStackingStackable
+	}
+	if promotion.Stacking != models2.PromotionStackingExclusive && promotion.Stacking != models2.PromotionStackingStackable {
+		return fmt.Errorf("unknown promotion stacking %q", promotion.Stacking)
+	}
+
+	promotion.Currency = strings.ToUpper(promotion.Currency)
+	if promotion.Currency != "" && !currencyPattern.MatchString(promotion.Currency) {
+		return fmt.Errorf("currency must be an ISO 4217 code")
+	}
+	if promotion.MinPrice != nil && *promotion.MinPrice < 0 {
+		return fmt.Errorf("promotion minimum price must not be negative")
+	}
+	if !promotion.StartsAt.IsZero() && !promotion.EndsAt.IsZero() && !promotion.StartsAt.Before(promotion.EndsAt) {
+		return fmt.Errorf("promotion must start before it ends")
+	}
+	return nil
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
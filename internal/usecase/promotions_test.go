@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
+	"golang.org/x/net/context"
+	"products/config"
+	models2 "products/internal/models"
+	"products/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// promotionRepository adds running promotions and visible products to the
+// price list fake.
+type promotionRepository struct {
+	*priceListRepository
+
+	promotions []*models2.Promotion
+}
+
+func (r *promotionRepository) GetActivePromotions(context.Context, time.Time) ([]*models2.Promotion, error) {
+	return r.promotions, nil
+}
+
+func (r *promotionRepository) IsProductVisible(context.Context, int64, int64) (bool, error) {
+	return true, nil
+}
+
+func (r *promotionRepository) GetProduct(_ context.Context, input *models2.GetProductInput) (*productsv1.Product, error) {
+	return &productsv1.Product{Id: input.ID, CategoryId: 7}, nil
+}
+
+func TestQuotePricesPromotions(t *testing.T) {
+	percent := func(id int64, value float64, stacking models2.PromotionStacking) *models2.Promotion {
+		return &models2.Promotion{ID: id, Kind: models2.PromotionKindPercent, Value: value, Stacking: stacking}
+	}
+	fixed := func(id int64, value float64, stacking models2.PromotionStacking) *models2.Promotion {
+		return &models2.Promotion{ID: id, Kind: models2.PromotionKindFixed, Value: value, Stacking: stacking}
+	}
+	buyXGetY := func(id, buy, get int64) *models2.Promotion {
+		return &models2.Promotion{ID: id, Kind: models2.PromotionKindBuyXGetY, BuyQuantity: buy, GetQuantity: get, Stacking: models2.PromotionStackingStackable}
+	}
+
+	tests := []struct {
+		name       string
+		promotions []*models2.Promotion
+		quantity   int64
+		discount   float64
+		total      float64
+		// applied lists the IDs of the promotions applied, in order.
+		applied []int64
+	}{
+		{
+			name:     "no promotions",
+			quantity: 2,
+			total:    200,
+		},
+		{
+			name:       "stackable promotions apply in turn",
+			promotions: []*models2.Promotion{percent(1, 10, models2.PromotionStackingStackable), fixed(2, 5, models2.PromotionStackingStackable)},
+			quantity:   2,
+			discount:   30,
+			total:      170,
+			applied:    []int64{1, 2},
+		},
+		{
+			name:       "exclusive promotion first stops the rest",
+			promotions: []*models2.Promotion{percent(1, 20, models2.PromotionStackingExclusive), fixed(2, 5, models2.PromotionStackingStackable)},
+			quantity:   1,
+			discount:   20,
+			total:      80,
+			applied:    []int64{1},
+		},
+		{
+			name: "exclusive promotion after another is skipped",
+			promotions: []*models2.Promotion{
+				percent(1, 10, models2.PromotionStackingStackable),
+				percent(2, 50, models2.PromotionStackingExclusive),
+				fixed(3, 5, models2.PromotionStackingStackable),
+			},
+			quantity: 1,
+			discount: 15,
+			total:    85,
+			applied:  []int64{1, 3},
+		},
+		{
+			name:       "buy x get y on a multiple",
+			promotions: []*models2.Promotion{buyXGetY(1, 2, 1)},
+			quantity:   6,
+			discount:   200,
+			total:      400,
+			applied:    []int64{1},
+		},
+		{
+			name:       "buy x get y on a quantity that is not a multiple",
+			promotions: []*models2.Promotion{buyXGetY(1, 2, 1)},
+			quantity:   7,
+			discount:   200,
+			total:      500,
+			applied:    []int64{1},
+		},
+		{
+			name:       "buy x get y below the first free unit",
+			promotions: []*models2.Promotion{buyXGetY(1, 2, 1)},
+			quantity:   2,
+			total:      200,
+		},
+		{
+			name:       "discount larger than the price",
+			promotions: []*models2.Promotion{fixed(1, 150, models2.PromotionStackingStackable), percent(2, 10, models2.PromotionStackingStackable)},
+			quantity:   2,
+			discount:   200,
+			total:      0,
+			applied:    []int64{1},
+		},
+		{
+			name: "promotion for another category",
+			promotions: []*models2.Promotion{{
+				ID: 1, Kind: models2.PromotionKindPercent, Value: 10, Stacking: models2.PromotionStackingStackable, CategoryIDs: []int64{8},
+			}},
+			quantity: 1,
+			total:    100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &promotionRepository{
+				priceListRepository: &priceListRepository{basePrice: 100},
+				promotions:          tt.promotions,
+			}
+			log := logger.NewApiLogger(&config.Config{})
+			require.NoError(t, log.InitLogger())
+			cfg := &config.Config{}
+			cfg.Pricing.BaseCurrency = "RUB"
+			u := NewUseCase(cfg, repo, nil, log)
+
+			output, err := u.QuotePrices(context.Background(), &models2.QuotePricesInput{
+				Items: []*models2.QuoteItem{{ProductID: 1, Quantity: tt.quantity}},
+			})
+			require.NoError(t, err)
+			require.Len(t, output.Lines, 1)
+			line := output.Lines[0]
+			assert.Equal(t, 100*float64(tt.quantity), line.Subtotal)
+			assert.Equal(t, tt.discount, line.Discount)
+			assert.Equal(t, tt.total, line.Total)
+			assert.Equal(t, tt.total, output.Total)
+			assert.Equal(t, "RUB", output.Currency)
+
+			var applied []int64
+			for _, promotion := range line.Promotions {
+				applied = append(applied, promotion.PromotionID)
+			}
+			assert.Equal(t, tt.applied, applied)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"slices"
+)
+
+func (u *UseCase) AddProductRelation(ctx context.Context, input *models2.AddProductRelationInput) (*models2.ProductRelation, error) {
+	if err := validateProductRelation(input.ProductID, input.RelatedID, input.Type); err != nil {
+		return nil, err
+	}
+	if input.Position < 0 {
+		return nil, fmt.Errorf("relation position must not be negative")
+	}
+
+	relation, err := u.repo.AddProductRelation(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating product relation: %v", err)
+		return nil, err
+	}
+	return relation, nil
+}
+
+func (u *UseCase) SetProductRelations(ctx context.Context, input *models2.SetProductRelationsInput) (*models2.GetProductRelationsOutput, error) {
+	seen := make(map[int64]bool, len(input.RelatedIDs))
+	for _, id := range input.RelatedIDs {
+		if err := validateProductRelation(input.ProductID, id, input.Type); err != nil {
+			return nil, err
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("related product %d is listed twice", id)
+		}
+		seen[id] = true
+	}
+
+	if err := u.repo.SetProductRelations(ctx, input); err != nil {
+		u.logger.Errorf("Error setting product relations: %v", err)
+		return nil, err
+	}
+	return u.GetProductRelations(ctx, input.ProductID)
+}
+
+func (u *UseCase) DeleteProductRelation(ctx context.Context, relation *models2.ProductRelation) error {
+	if err := u.repo.DeleteProductRelation(ctx, relation); err != nil {
+		u.logger.Errorf("Error deleting product relation: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetProductRelations(ctx context.Context, productID int64) (*models2.GetProductRelationsOutput, error) {
+	relations, err := u.repo.GetProductRelations(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product relations: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductRelationsOutput{
+		Relations: relations,
+	}, nil
+}
+
+// relatedProducts fetches the related products embedded in GetProduct. They
+// are held to the same visibility rules as the product itself and priced
+// from the same price list and currency.
+func (u *UseCase) relatedProducts(ctx context.Context, productID int64, group *models2.CustomerGroup, input *models2.GetProductInput) ([]*models2.RelatedProduct, error) {
+	filter := models2.ProductFilter{Locales: input.Locales}
+	if input.IncludeUnpublished {
+		filter.Statuses = models2.ProductStatuses
+	}
+	scopeVisibility(ctx, group, &filter)
+
+	related, err := u.repo.GetRelatedProducts(ctx, productID, &filter)
+	if err != nil {
+		u.logger.Errorf("Error fetching related products: %v", err)
+		return nil, err
+	}
+	bundles, err := u.repo.GetComponentPricedBundleIDs(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching bundles: %v", err)
+		return nil, err
+	}
+
+	for _, item := range related {
+		if input.PriceList == "" && input.Currency == "" && !slices.Contains(bundles, item.Product.Id) {
+			continue
+		}
+		price, err := u.GetProductPrice(ctx, &models2.GetProductPriceInput{
+			ProductID: item.Product.Id,
+			PriceList: input.PriceList,
+			Currency:  input.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+		item.Product.Price = float32(price.Price)
+	}
+	return related, nil
+}
+
+func validateProductRelation(productID, relatedID int64, relationType models2.RelationType) error {
+	if !slices.Contains(models2.RelationTypes, relationType) {
+		return fmt.Errorf("unknown relation type %q", relationType)
+	}
+	if productID == relatedID {
+		return fmt.Errorf("product cannot be related to itself")
+	}
+	return nil
+}
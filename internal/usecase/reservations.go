@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"regexp"
+	"time"
+)
+
+const (
+	defaultReservationTTL = 15 * time.Minute
+	maxReservationTTL     = 24 * time.Hour
+	reaperBatchSize       = 100
+)
+
+var reservationIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ReserveStock holds stock for the items. A bundle holds its components in
+// the same warehouse, so the reservation lists the components in its place.
+func (u *UseCase) ReserveStock(ctx context.Context, input *models2.ReserveStockInput) (*models2.ReservationOutput, error) {
+	if len(input.Items) == 0 {
+		return nil, fmt.Errorf("reservation must contain at least one item")
+	}
+	if input.TTL == 0 {
+		input.TTL = defaultReservationTTL
+	}
+	if input.TTL < 0 || input.TTL > maxReservationTTL {
+		return nil, fmt.Errorf("reservation ttl must be between 0 and %s", maxReservationTTL)
+	}
+
+	var expanded []*models2.ReservationItem
+	for _, item := range input.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("reserved quantity for product %d must be positive", item.ProductID)
+		}
+		bundle, err := u.repo.GetBundle(ctx, item.ProductID)
+		if err != nil {
+			u.logger.Errorf("Error fetching bundle: %v", err)
+			return nil, err
+		}
+		if bundle == nil {
+			expanded = append(expanded, item)
+			continue
+		}
+		for _, component := range bundle.Components {
+			expanded = append(expanded, &models2.ReservationItem{
+				ProductID:   component.ProductID,
+				WarehouseID: item.WarehouseID,
+				Quantity:    item.Quantity * component.Quantity,
+			})
+		}
+	}
+
+	type itemKey struct{ productID, warehouseID int64 }
+	merged := make(map[itemKey]*models2.ReservationItem, len(expanded))
+	items := make([]*models2.ReservationItem, 0, len(expanded))
+	for _, item := range expanded {
+		key := itemKey{item.ProductID, item.WarehouseID}
+		if existing, ok := merged[key]; ok {
+			existing.Quantity += item.Quantity
+			continue
+		}
+		item := *item
+		merged[key] = &item
+		items = append(items, &item)
+	}
+	input.Items = items
+
+	reservation, err := u.repo.ReserveStock(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error reserving stock: %v", err)
+		return nil, err
+	}
+
+	return &models2.ReservationOutput{
+		Reservation: reservation,
+	}, nil
+}
+
+func (u *UseCase) CommitReservation(ctx context.Context, id string) (*models2.ReservationOutput, error) {
+	if !reservationIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("reservation not found")
+	}
+
+	reservation, err := u.repo.CommitReservation(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error committing reservation: %v", err)
+		return nil, err
+	}
+
+	return &models2.ReservationOutput{
+		Reservation: reservation,
+	}, nil
+}
+
+func (u *UseCase) ReleaseReservation(ctx context.Context, id string) (*models2.ReservationOutput, error) {
+	if !reservationIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("reservation not found")
+	}
+
+	reservation, err := u.repo.ReleaseReservation(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error releasing reservation: %v", err)
+		return nil, err
+	}
+
+	return &models2.ReservationOutput{
+		Reservation: reservation,
+	}, nil
+}
+
+// ReleaseExpiredReservations expires held reservations in batches until none are left.
+func (u *UseCase) ReleaseExpiredReservations(ctx context.Context) (*models2.ReleaseExpiredReservationsOutput, error) {
+	var output models2.ReleaseExpiredReservationsOutput
+	for {
+		released, err := u.repo.ReleaseExpiredReservations(ctx, reaperBatchSize)
+		if err != nil {
+			u.logger.Errorf("Error releasing expired reservations: %v", err)
+			return nil, err
+		}
+		output.Released += released
+		if released < reaperBatchSize {
+			return &output, nil
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"golang.org/x/net/context"
+	"products/config"
+	repository "products/internal"
+	models2 "products/internal/models"
+	"products/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reservationRepository fakes bundles and stock reservations; any other
+// repository method panics.
+type reservationRepository struct {
+	repository.Postgres
+
+	bundles  map[int64]*models2.Bundle
+	reserved *models2.ReserveStockInput
+}
+
+func (r *reservationRepository) GetBundle(_ context.Context, productID int64) (*models2.Bundle, error) {
+	return r.bundles[productID], nil
+}
+
+func (r *reservationRepository) ReserveStock(_ context.Context, input *models2.ReserveStockInput) (*models2.Reservation, error) {
+	r.reserved = input
+	return &models2.Reservation{Status: models2.ReservationStatusHeld, Items: input.Items}, nil
+}
+
+func TestReserveStockExpandsBundles(t *testing.T) {
+	repo := &reservationRepository{bundles: map[int64]*models2.Bundle{
+		10: {ProductID: 10, Components: []*models2.BundleComponent{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}},
+	}}
+	log := logger.NewApiLogger(&config.Config{})
+	require.NoError(t, log.InitLogger())
+	u := NewUseCase(&config.Config{}, repo, nil, log)
+
+	_, err := u.ReserveStock(context.Background(), &models2.ReserveStockInput{
+		Items: []*models2.ReservationItem{
+			{ProductID: 10, WarehouseID: 1, Quantity: 3},
+			{ProductID: 1, WarehouseID: 1, Quantity: 1},
+			{ProductID: 10, WarehouseID: 2, Quantity: 1},
+		},
+		TTL: time.Minute,
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []*models2.ReservationItem{
+		{ProductID: 1, WarehouseID: 1, Quantity: 7},
+		{ProductID: 2, WarehouseID: 1, Quantity: 3},
+		{ProductID: 1, WarehouseID: 2, Quantity: 2},
+		{ProductID: 2, WarehouseID: 2, Quantity: 1},
+	}, repo.reserved.Items)
+}
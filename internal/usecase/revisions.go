@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"sort"
+)
+
+// productSnapshot holds the fields of a product revision that can be reverted.
+type productSnapshot struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Price       float64 `json:"price"`
+	CategoryID  *int64  `json:"category_id"`
+	// Attributes is missing in revisions written before attributes existed.
+	Attributes map[string]any `json:"attributes"`
+}
+
+func (u *UseCase) GetProductRevisions(ctx context.Context, productID int64) (*models2.GetProductRevisionsOutput, error) {
+	if err := checkStaffRead(ctx); err != nil {
+		return nil, err
+	}
+
+	revisions, err := u.repo.GetProductRevisions(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product revisions: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductRevisionsOutput{
+		Revisions: revisions,
+	}, nil
+}
+
+func (u *UseCase) DiffProductVersions(ctx context.Context, input *models2.DiffProductVersionsInput) (*models2.DiffProductVersionsOutput, error) {
+	if err := checkStaffRead(ctx); err != nil {
+		return nil, err
+	}
+
+	from, err := u.repo.GetProductRevision(ctx, input.ProductID, input.FromRevision)
+	if err != nil {
+		u.logger.Errorf("Error fetching product revision %d: %v", input.FromRevision, err)
+		return nil, err
+	}
+	to, err := u.repo.GetProductRevision(ctx, input.ProductID, input.ToRevision)
+	if err != nil {
+		u.logger.Errorf("Error fetching product revision %d: %v", input.ToRevision, err)
+		return nil, err
+	}
+
+	var fromFields, toFields map[string]json.RawMessage
+	if err = json.Unmarshal(from.Snapshot, &fromFields); err != nil {
+		return nil, fmt.Errorf("decode revision %d: %w", from.Revision, err)
+	}
+	if err = json.Unmarshal(to.Snapshot, &toFields); err != nil {
+		return nil, fmt.Errorf("decode revision %d: %w", to.Revision, err)
+	}
+
+	fields := make([]string, 0, len(fromFields)+len(toFields))
+	for field := range fromFields {
+		fields = append(fields, field)
+	}
+	for field := range toFields {
+		if _, ok := fromFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []*models2.FieldChange
+	for _, field := range fields {
+		if !bytes.Equal(fromFields[field], toFields[field]) {
+			changes = append(changes, &models2.FieldChange{
+				Field: field,
+				From:  fromFields[field],
+				To:    toFields[field],
+			})
+		}
+	}
+
+	return &models2.DiffProductVersionsOutput{
+		Changes: changes,
+	}, nil
+}
+
+// RevertProduct writes the fields of an earlier revision back to the product,
+// which records a new revision.
+func (u *UseCase) RevertProduct(ctx context.Context, input *models2.RevertProductInput) (*models2.UpdateProductOutput, error) {
+	rev, err := u.repo.GetProductRevision(ctx, input.ProductID, input.Revision)
+	if err != nil {
+		u.logger.Errorf("Error fetching product revision: %v", err)
+		return nil, err
+	}
+
+	var snapshot productSnapshot
+	if err = json.Unmarshal(rev.Snapshot, &snapshot); err != nil {
+		return nil, fmt.Errorf("decode revision %d: %w", rev.Revision, err)
+	}
+
+	update := &models2.UpdateProductInput{
+		ID:         input.ProductID,
+		Price:      snapshot.Price,
+		Attributes: snapshot.Attributes,
+	}
+	if snapshot.Name != nil {
+		update.Name = *snapshot.Name
+	}
+	if snapshot.Description != nil {
+		update.Description = *snapshot.Description
+	}
+	if snapshot.CategoryID != nil {
+		update.CategoryID = *snapshot.CategoryID
+	}
+
+	return u.UpdateProduct(ctx, update)
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"time"
+)
+
+func (u *UseCase) CreateScheduledPrice(ctx context.Context, input *models2.ScheduledPrice) (*models2.ScheduledPriceOutput, error) {
+	if input.Price < 0 {
+		return nil, fmt.Errorf("price must not be negative")
+	}
+	if input.StartsAt.IsZero() {
+		return nil, fmt.Errorf("scheduled price needs a start time")
+	}
+	if !input.EndsAt.IsZero() {
+		if !input.StartsAt.Before(input.EndsAt) {
+			return nil, fmt.Errorf("scheduled price must start before it ends")
+		}
+		if !input.EndsAt.After(time.Now()) {
+			return nil, fmt.Errorf("scheduled price must end in the future")
+		}
+	}
+
+	scheduledPrice, err := u.repo.CreateScheduledPrice(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating scheduled price: %v", err)
+		return nil, err
+	}
+
+	return &models2.ScheduledPriceOutput{
+		ScheduledPrice: scheduledPrice,
+	}, nil
+}
+
+func (u *UseCase) CancelScheduledPrice(ctx context.Context, id int64) (*models2.ScheduledPriceOutput, error) {
+	scheduledPrice, err := u.repo.CancelScheduledPrice(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error cancelling scheduled price: %v", err)
+		return nil, err
+	}
+
+	return &models2.ScheduledPriceOutput{
+		ScheduledPrice: scheduledPrice,
+	}, nil
+}
+
+func (u *UseCase) GetScheduledPrices(ctx context.Context, input *models2.GetScheduledPricesInput) (*models2.GetScheduledPricesOutput, error) {
+	for _, status := range input.Statuses {
+		switch status {
+		case models2.ScheduledPriceStatusPending, models2.ScheduledPriceStatusActive,
+			models2.ScheduledPriceStatusFinished, models2.ScheduledPriceStatusCancelled:
+		default:
+			return nil, fmt.Errorf("unknown scheduled price status %q", status)
+		}
+	}
+
+	scheduledPrices, err := u.repo.GetScheduledPrices(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching scheduled prices: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetScheduledPricesOutput{
+		ScheduledPrices: scheduledPrices,
+	}, nil
+}
+
+func (u *UseCase) ApplyScheduledPrices(ctx context.Context) (*models2.ApplyScheduledPricesOutput, error) {
+	output, err := u.repo.ApplyScheduledPrices(ctx, time.Now())
+	if err != nil {
+		u.logger.Errorf("Error applying scheduled prices: %v", err)
+		return nil, err
+	}
+	return output, nil
+}
+
+func (u *UseCase) GetPriceHistory(ctx context.Context, input *models2.GetPriceHistoryInput) (*models2.GetPriceHistoryOutput, error) {
+	if input.Limit <= 0 {
+		input.Limit = defaultHistoryLimit
+	}
+	if input.Limit > maxHistoryLimit {
+		input.Limit = maxHistoryLimit
+	}
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if !input.From.IsZero() && !input.To.IsZero() && !input.From.Before(input.To) {
+		return nil, fmt.Errorf("history range start must be before its end")
+	}
+
+	entries, total, err := u.repo.GetPriceHistory(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching price history: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetPriceHistoryOutput{
+		Entries: entries,
+		Total:   total,
+	}, nil
+}
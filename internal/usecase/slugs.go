@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"products/pkg/slug"
+)
+
+// GetProductBySlug returns the product with the slug. A former slug of the
+// product resolves too, with Redirected set so that callers can redirect to
+// the current one.
+func (u *UseCase) GetProductBySlug(ctx context.Context, input *models2.GetProductBySlugInput) (*models2.GetProductOutput, error) {
+	id, redirected, err := u.repo.GetProductIDBySlug(ctx, input.Slug)
+	if err != nil {
+		u.logger.Errorf("Error resolving product slug: %v", err)
+		return nil, err
+	}
+
+	input.ID = id
+	output, err := u.GetProduct(ctx, &input.GetProductInput)
+	if err != nil {
+		return nil, err
+	}
+	output.Redirected = redirected
+	return output, nil
+}
+
+// GetProductCategoryBySlug returns the category with the slug, resolving
+// former slugs like GetProductBySlug.
+func (u *UseCase) GetProductCategoryBySlug(ctx context.Context, input *models2.GetProductCategoryBySlugInput) (*models2.GetProductCategoryOutput, error) {
+	id, redirected, err := u.repo.GetCategoryIDBySlug(ctx, input.Slug)
+	if err != nil {
+		u.logger.Errorf("Error resolving category slug: %v", err)
+		return nil, err
+	}
+
+	input.ID = id
+	output, err := u.GetProductCategory(ctx, &input.GetProductCategoryInput)
+	if err != nil {
+		return nil, err
+	}
+	output.Redirected = redirected
+	return output, nil
+}
+
+func validateSlug(s string) error {
+	if s != "" && !slug.Valid(s) {
+		return fmt.Errorf("invalid slug %q: use lowercase latin letters and digits separated by single hyphens, up to %d characters", s, slug.MaxLength)
+	}
+	return nil
+}
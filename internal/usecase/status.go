@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"products/pkg/reqctx"
+	"slices"
+)
+
+// productTransitions is the status workflow: the allowed target statuses of
+// every status and the roles that may move a product there.
+var productTransitions = map[models2.ProductStatus]map[models2.ProductStatus][]string{
+	models2.ProductStatusDraft: {
+		models2.ProductStatusInReview: {models2.ActorRoleEditor, models2.ActorRoleAdmin},
+		models2.ProductStatusArchived: {models2.ActorRoleEditor, models2.ActorRoleAdmin},
+	},
+	models2.ProductStatusInReview: {
+		models2.ProductStatusDraft:     {models2.ActorRoleReviewer, models2.ActorRoleAdmin},
+		models2.ProductStatusPublished: {models2.ActorRoleReviewer, models2.ActorRoleAdmin},
+	},
+	models2.ProductStatusPublished: {
+		models2.ProductStatusDraft:        {models2.ActorRoleAdmin},
+		models2.ProductStatusDiscontinued: {models2.ActorRoleEditor, models2.ActorRoleAdmin, models2.ActorRoleSystem},
+	},
+	models2.ProductStatusDiscontinued: {
+		models2.ProductStatusPublished: {models2.ActorRoleAdmin, models2.ActorRoleSystem},
+		models2.ProductStatusArchived:  {models2.ActorRoleEditor, models2.ActorRoleAdmin},
+	},
+	models2.ProductStatusArchived: {
+		models2.ProductStatusDraft: {models2.ActorRoleAdmin},
+	},
+}
+
+// staffRoles may read deleted products and categories and products outside
+// the storefront, such as drafts, products out of their availability window
+// or products restricted to customer groups.
+var staffRoles = []string{models2.ActorRoleEditor, models2.ActorRoleReviewer, models2.ActorRoleAdmin}
+
+func isStaff(ctx context.Context) bool {
+	return slices.Contains(staffRoles, reqctx.Role(ctx))
+}
+
+func checkStaffRead(ctx context.Context) error {
+	if !isStaff(ctx) {
+		return fmt.Errorf("role %q may not read deleted or unpublished items", reqctx.Role(ctx))
+	}
+	return nil
+}
+
+// checkStaffRestore allows only staff to bring back deleted items.
+func checkStaffRestore(ctx context.Context) error {
+	if !isStaff(ctx) {
+		return fmt.Errorf("role %q may not restore deleted items", reqctx.Role(ctx))
+	}
+	return nil
+}
+
+// checkProductFilterRead allows a filter beyond the storefront to staff only.
+func checkProductFilterRead(ctx context.Context, filter *models2.ProductFilter) error {
+	if filter.IncludeDeleted || len(filter.Statuses) > 0 {
+		return checkStaffRead(ctx)
+	}
+	return nil
+}
+
+// TransitionProductStatus moves a product along the status workflow if the
+// caller's role may perform the transition.
+func (u *UseCase) TransitionProductStatus(ctx context.Context, input *models2.TransitionProductStatusInput) (*models2.TransitionProductStatusOutput, error) {
+	if !slices.Contains(models2.ProductStatuses, input.To) {
+		return nil, fmt.Errorf("unknown product status %q", input.To)
+	}
+
+	from, err := u.repo.GetProductStatus(ctx, input.ProductID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product status: %v", err)
+		return nil, err
+	}
+	if err = checkProductTransition(from, input.To, reqctx.Role(ctx)); err != nil {
+		return nil, err
+	}
+
+	transition, err := u.repo.TransitionProductStatus(ctx, input, from)
+	if err != nil {
+		u.logger.Errorf("Error changing product status: %v", err)
+		return nil, err
+	}
+
+	return &models2.TransitionProductStatusOutput{
+		Transition: transition,
+	}, nil
+}
+
+func (u *UseCase) GetProductTransitions(ctx context.Context, productID int64) (*models2.GetProductTransitionsOutput, error) {
+	transitions, err := u.repo.GetProductTransitions(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product status transitions: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductTransitionsOutput{
+		Transitions: transitions,
+	}, nil
+}
+
+// transitionSources returns the statuses role may move a product to to from.
+func transitionSources(to models2.ProductStatus, role string) []models2.ProductStatus {
+	var sources []models2.ProductStatus
+	for _, from := range models2.ProductStatuses {
+		if from != to && checkProductTransition(from, to, role) == nil {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+func checkProductTransition(from, to models2.ProductStatus, role string) error {
+	if from == to {
+		return fmt.Errorf("product is already %s", to)
+	}
+	roles, ok := productTransitions[from][to]
+	if !ok {
+		return fmt.Errorf("product cannot move from %s to %s", from, to)
+	}
+	if !slices.Contains(roles, role) {
+		return fmt.Errorf("role %q may not move a product from %s to %s", role, from, to)
+	}
+	return nil
+}
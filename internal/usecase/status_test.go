@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"golang.org/x/net/context"
+	"products/config"
+	repository "products/internal"
+	models2 "products/internal/models"
+	"products/pkg/logger"
+	"products/pkg/reqctx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckProductTransition(t *testing.T) {
+	tests := []struct {
+		from, to models2.ProductStatus
+		role     string
+		allowed  bool
+	}{
+		{models2.ProductStatusDraft, models2.ProductStatusInReview, models2.ActorRoleEditor, true},
+		{models2.ProductStatusDraft, models2.ProductStatusInReview, models2.ActorRoleReviewer, false},
+		{models2.ProductStatusDraft, models2.ProductStatusInReview, "", false},
+		{models2.ProductStatusDraft, models2.ProductStatusPublished, models2.ActorRoleAdmin, false},
+		{models2.ProductStatusDraft, models2.ProductStatusArchived, models2.ActorRoleEditor, true},
+		{models2.ProductStatusInReview, models2.ProductStatusPublished, models2.ActorRoleReviewer, true},
+		{models2.ProductStatusInReview, models2.ProductStatusPublished, models2.ActorRoleEditor, false},
+		{models2.ProductStatusInReview, models2.ProductStatusDraft, models2.ActorRoleReviewer, true},
+		{models2.ProductStatusPublished, models2.ProductStatusDraft, models2.ActorRoleAdmin, true},
+		{models2.ProductStatusPublished, models2.ProductStatusDraft, models2.ActorRoleEditor, false},
+		{models2.ProductStatusPublished, models2.ProductStatusDiscontinued, models2.ActorRoleEditor, true},
+		{models2.ProductStatusPublished, models2.ProductStatusDiscontinued, models2.ActorRoleSystem, true},
+		{models2.ProductStatusPublished, models2.ProductStatusArchived, models2.ActorRoleAdmin, false},
+		{models2.ProductStatusDiscontinued, models2.ProductStatusPublished, models2.ActorRoleSystem, true},
+		{models2.ProductStatusDiscontinued, models2.ProductStatusPublished, models2.ActorRoleEditor, false},
+		{models2.ProductStatusDiscontinued, models2.ProductStatusArchived, models2.ActorRoleEditor, true},
+		{models2.ProductStatusArchived, models2.ProductStatusDraft, models2.ActorRoleAdmin, true},
+		{models2.ProductStatusArchived, models2.ProductStatusDraft, models2.ActorRoleEditor, false},
+		{models2.ProductStatusArchived, models2.ProductStatusPublished, models2.ActorRoleAdmin, false},
+		{models2.ProductStatusPublished, models2.ProductStatusPublished, models2.ActorRoleAdmin, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to)+" as "+tt.role, func(t *testing.T) {
+			err := checkProductTransition(tt.from, tt.to, tt.role)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTransitionSources(t *testing.T) {
+	tests := []struct {
+		role string
+		want []models2.ProductStatus
+	}{
+		{models2.ActorRoleEditor, []models2.ProductStatus{models2.ProductStatusDraft, models2.ProductStatusDiscontinued}},
+		{models2.ActorRoleAdmin, []models2.ProductStatus{models2.ProductStatusDraft, models2.ProductStatusDiscontinued}},
+		{models2.ActorRoleReviewer, nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			assert.Equal(t, tt.want, transitionSources(models2.ProductStatusArchived, tt.role))
+		})
+	}
+}
+
+// categoryRepository fakes the category deletion of the repository; any
+// other repository method panics.
+type categoryRepository struct {
+	repository.Postgres
+
+	input *models2.DeleteProductCategoryInput
+}
+
+func (r *categoryRepository) DeleteProductCategory(_ context.Context, input *models2.DeleteProductCategoryInput) (int64, error) {
+	r.input = input
+	return 0, nil
+}
+
+func TestDeleteProductCategoryArchivesFromAllowedStatuses(t *testing.T) {
+	log := logger.NewApiLogger(&config.Config{})
+	require.NoError(t, log.InitLogger())
+	repo := &categoryRepository{}
+	u := NewUseCase(&config.Config{}, repo, nil, log)
+
+	ctx := reqctx.WithRole(context.Background(), models2.ActorRoleEditor)
+	_, err := u.DeleteProductCategory(ctx, &models2.DeleteProductCategoryInput{ID: 1, Policy: models2.CategoryDeletePolicyArchive})
+	require.NoError(t, err)
+	assert.Equal(t, []models2.ProductStatus{models2.ProductStatusDraft, models2.ProductStatusDiscontinued}, repo.input.ArchiveFrom)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"strings"
+)
+
+func (u *UseCase) CreateWarehouse(ctx context.Context, input *models2.Warehouse) (*models2.WarehouseOutput, error) {
+	if err := validateWarehouse(input); err != nil {
+		return nil, err
+	}
+
+	warehouse, err := u.repo.CreateWarehouse(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating warehouse: %v", err)
+		return nil, err
+	}
+
+	return &models2.WarehouseOutput{
+		Warehouse: warehouse,
+	}, nil
+}
+
+func (u *UseCase) UpdateWarehouse(ctx context.Context, input *models2.Warehouse) (*models2.WarehouseOutput, error) {
+	if err := validateWarehouse(input); err != nil {
+		return nil, err
+	}
+
+	warehouse, err := u.repo.UpdateWarehouse(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating warehouse: %v", err)
+		return nil, err
+	}
+
+	return &models2.WarehouseOutput{
+		Warehouse: warehouse,
+	}, nil
+}
+
+func (u *UseCase) GetWarehouses(ctx context.Context) (*models2.GetWarehousesOutput, error) {
+	warehouses, err := u.repo.GetWarehouses(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching warehouses: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetWarehousesOutput{
+		Warehouses: warehouses,
+	}, nil
+}
+
+func (u *UseCase) AdjustStock(ctx context.Context, input *models2.AdjustStockInput) (*models2.AdjustStockOutput, error) {
+	if input.Delta == 0 {
+		return nil, fmt.Errorf("stock adjustment must not be zero")
+	}
+	switch input.Reason {
+	case models2.StockReasonReceipt, models2.StockReasonReturn:
+		if input.Delta < 0 {
+			return nil, fmt.Errorf("%s adjustments must add stock", input.Reason)
+		}
+	case models2.StockReasonSale, models2.StockReasonDamage:
+		if input.Delta > 0 {
+			return nil, fmt.Errorf("%s adjustments must deduct stock", input.Reason)
+		}
+	case models2.StockReasonCorrection:
+	default:
+		return nil, fmt.Errorf("unknown stock reason %q", input.Reason)
+	}
+
+	level, err := u.repo.AdjustStock(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error adjusting stock: %v", err)
+		return nil, err
+	}
+
+	return &models2.AdjustStockOutput{
+		Level: level,
+	}, nil
+}
+
+func (u *UseCase) GetProductAvailability(ctx context.Context, productID int64) (*models2.GetProductAvailabilityOutput, error) {
+	bundle, err := u.repo.GetBundle(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching bundle: %v", err)
+		return nil, err
+	}
+	if bundle != nil {
+		return u.bundleAvailability(ctx, bundle)
+	}
+
+	levels, err := u.repo.GetProductAvailability(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product availability: %v", err)
+		return nil, err
+	}
+
+	output := &models2.GetProductAvailabilityOutput{
+		Levels: levels,
+	}
+	for _, level := range levels {
+		output.OnHand += level.OnHand
+		output.Reserved += level.Reserved
+		output.Available += level.Available
+	}
+	return output, nil
+}
+
+func validateWarehouse(warehouse *models2.Warehouse) error {
+	warehouse.Code = strings.TrimSpace(warehouse.Code)
+	if warehouse.Code == "" {
+		return fmt.Errorf("warehouse code must not be empty")
+	}
+	if warehouse.Name == "" {
+		warehouse.Name = warehouse.Code
+	}
+	return nil
+}
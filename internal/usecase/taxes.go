@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"strings"
+	"time"
+)
+
+func (u *UseCase) CreateTaxClass(ctx context.Context, input *models2.TaxClass) (*models2.TaxClassOutput, error) {
+	input.Code = strings.TrimSpace(input.Code)
+	if input.Code == "" {
+		return nil, fmt.Errorf("tax class code must not be empty")
+	}
+	if input.Name == "" {
+		input.Name = input.Code
+	}
+
+	taxClass, err := u.repo.CreateTaxClass(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating tax class: %v", err)
+		return nil, err
+	}
+
+	return &models2.TaxClassOutput{
+		TaxClass: taxClass,
+	}, nil
+}
+
+func (u *UseCase) GetTaxClasses(ctx context.Context) (*models2.GetTaxClassesOutput, error) {
+	taxClasses, err := u.repo.GetTaxClasses(ctx)
+	if err != nil {
+		u.logger.Errorf("Error fetching tax classes: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetTaxClassesOutput{
+		TaxClasses: taxClasses,
+	}, nil
+}
+
+func (u *UseCase) SetTaxRegion(ctx context.Context, input *models2.TaxRegion) error {
+	input.Code = strings.TrimSpace(input.Code)
+	if input.Code == "" {
+		return fmt.Errorf("tax region code must not be empty")
+	}
+	if input.Name == "" {
+		input.Name = input.Code
+	}
+
+	if err := u.repo.SetTaxRegion(ctx, input); err != nil {
+		u.logger.Errorf("Error setting tax region: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) CreateTaxRate(ctx context.Context, input *models2.TaxRate) (*models2.TaxRateOutput, error) {
+	if input.Rate < 0 || input.Rate > 100 {
+		return nil, fmt.Errorf("tax rate must be between 0 and 100 percent")
+	}
+	if input.EffectiveFrom.IsZero() {
+		return nil, fmt.Errorf("tax rate needs an effective date")
+	}
+
+	taxRate, err := u.repo.CreateTaxRate(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating tax rate: %v", err)
+		return nil, err
+	}
+
+	return &models2.TaxRateOutput{
+		TaxRate: taxRate,
+	}, nil
+}
+
+func (u *UseCase) SetProductTaxClass(ctx context.Context, input *models2.SetTaxClassInput) error {
+	if err := u.repo.SetProductTaxClass(ctx, input); err != nil {
+		u.logger.Errorf("Error setting product tax class: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) SetCategoryTaxClass(ctx context.Context, input *models2.SetTaxClassInput) error {
+	if err := u.repo.SetCategoryTaxClass(ctx, input); err != nil {
+		u.logger.Errorf("Error setting category tax class: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ComputeTax splits line amounts into net, tax and gross with the rates in
+// effect in the region. Amounts are taken as gross when PRICES_INCLUDE_TAX is
+// set and as net otherwise; every line is rounded to cents on its own and the
+// totals are sums of the rounded lines.
+func (u *UseCase) ComputeTax(ctx context.Context, input *models2.ComputeTaxInput) (*models2.ComputeTaxOutput, error) {
+	region, err := u.repo.GetTaxRegion(ctx, input.Region)
+	if err != nil {
+		u.logger.Errorf("Error fetching tax region: %v", err)
+		return nil, err
+	}
+	at := input.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	productIDs := make([]int64, 0, len(input.Lines))
+	for _, line := range input.Lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	rates, err := u.repo.GetProductTaxRates(ctx, region.Code, at, productIDs)
+	if err != nil {
+		u.logger.Errorf("Error fetching product tax rates: %v", err)
+		return nil, err
+	}
+
+	output := &models2.ComputeTaxOutput{
+		Region:       region.Code,
+		DisplayGross: region.DisplayGross,
+	}
+	for _, line := range input.Lines {
+		rate, ok := rates[line.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("product %d not found", line.ProductID)
+		}
+
+		amount := &models2.TaxAmount{
+			ProductID:    line.ProductID,
+			TaxClassCode: rate.TaxClassCode,
+			Rate:         rate.Rate,
+		}
+		if u.cfg.Pricing.PricesIncludeTax {
+			amount.Gross = roundCents(line.Amount)
+			amount.Tax = roundCents(amount.Gross * rate.Rate / (100 + rate.Rate))
+			amount.Net = roundCents(amount.Gross - amount.Tax)
+		} else {
+			amount.Net = roundCents(line.Amount)
+			amount.Tax = roundCents(amount.Net * rate.Rate / 100)
+			amount.Gross = roundCents(amount.Net + amount.Tax)
+		}
+		amount.Display = amount.Net
+		if region.DisplayGross {
+			amount.Display = amount.Gross
+		}
+
+		output.Lines = append(output.Lines, amount)
+		output.Net = roundCents(output.Net + amount.Net)
+		output.Tax = roundCents(output.Tax + amount.Tax)
+		output.Gross = roundCents(output.Gross + amount.Gross)
+	}
+
+	return output, nil
+}
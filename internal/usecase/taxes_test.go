@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"golang.org/x/net/context"
+	"products/config"
+	repository "products/internal"
+	models2 "products/internal/models"
+	"products/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taxRepository fakes the tax regions and rates of the repository; any other
+// repository method panics.
+type taxRepository struct {
+	repository.Postgres
+
+	region *models2.TaxRegion
+	// rates are the tax rates by product ID.
+	rates map[int64]float64
+}
+
+func (r *taxRepository) GetTaxRegion(context.Context, string) (*models2.TaxRegion, error) {
+	return r.region, nil
+}
+
+func (r *taxRepository) GetProductTaxRates(_ context.Context, _ string, _ time.Time, productIDs []int64) (map[int64]*models2.ProductTaxRate, error) {
+	rates := make(map[int64]*models2.ProductTaxRate, len(productIDs))
+	for _, id := range productIDs {
+		if rate, ok := r.rates[id]; ok {
+			rates[id] = &models2.ProductTaxRate{ProductID: id, TaxClassCode: "standard", Rate: rate}
+		}
+	}
+	return rates, nil
+}
+
+func TestComputeTaxRounding(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeTax   bool
+		displayGross bool
+		lines        []*models2.TaxableLine
+		// want holds the net, tax and gross of each line, then of the total.
+		want    [][3]float64
+		display []float64
+		wantErr bool
+	}{
+		{
+			name:         "gross prices",
+			includeTax:   true,
+			displayGross: true,
+			lines:        []*models2.TaxableLine{{ProductID: 1, Amount: 100}},
+			want:         [][3]float64{{83.33, 16.67, 100}, {83.33, 16.67, 100}},
+			display:      []float64{100},
+		},
+		{
+			name:       "net prices",
+			includeTax: false,
+			lines:      []*models2.TaxableLine{{ProductID: 1, Amount: 19.99}},
+			want:       [][3]float64{{19.99, 4, 23.99}, {19.99, 4, 23.99}},
+			display:    []float64{19.99},
+		},
+		{
+			name:       "net amount rounded to cents first",
+			includeTax: false,
+			lines:      []*models2.TaxableLine{{ProductID: 1, Amount: 10.125}},
+			want:       [][3]float64{{10.13, 2.03, 12.16}, {10.13, 2.03, 12.16}},
+			display:    []float64{10.13},
+		},
+		{
+			name:         "lines rounded one by one",
+			includeTax:   true,
+			displayGross: true,
+			lines:        []*models2.TaxableLine{{ProductID: 1, Amount: 10}, {ProductID: 1, Amount: 10}},
+			want:         [][3]float64{{8.33, 1.67, 10}, {8.33, 1.67, 10}, {16.66, 3.34, 20}},
+			display:      []float64{10, 10},
+		},
+		{
+			name:       "zero-rated product",
+			includeTax: true,
+			lines:      []*models2.TaxableLine{{ProductID: 2, Amount: 49.9}},
+			want:       [][3]float64{{49.9, 0, 49.9}, {49.9, 0, 49.9}},
+			display:    []float64{49.9},
+		},
+		{
+			name:    "unknown product",
+			lines:   []*models2.TaxableLine{{ProductID: 3, Amount: 10}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &taxRepository{
+				region: &models2.TaxRegion{Code: "RU", DisplayGross: tt.displayGross},
+				rates:  map[int64]float64{1: 20, 2: 0},
+			}
+			log := logger.NewApiLogger(&config.Config{})
+			require.NoError(t, log.InitLogger())
+			cfg := &config.Config{}
+			cfg.Pricing.PricesIncludeTax = tt.includeTax
+			u := NewUseCase(cfg, repo, nil, log)
+
+			output, err := u.ComputeTax(context.Background(), &models2.ComputeTaxInput{Region: "RU", Lines: tt.lines})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, output.Lines, len(tt.lines))
+			for i, line := range output.Lines {
+				assert.Equal(t, tt.want[i], [3]float64{line.Net, line.Tax, line.Gross}, "line %d", i)
+				assert.Equal(t, tt.display[i], line.Display, "line %d", i)
+			}
+			assert.Equal(t, tt.want[len(tt.want)-1], [3]float64{output.Net, output.Tax, output.Gross})
+		})
+	}
+}
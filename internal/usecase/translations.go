@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"products/pkg/reqctx"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// localePattern accepts a language with an optional region, e.g. "en" or "en-US".
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// normalizeLocale canonicalizes the case and separator of a locale such as
+// "EN_us" to "en-US" and reports whether the result is a valid locale.
+func normalizeLocale(locale string) (string, bool) {
+	language, region, found := strings.Cut(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	locale = strings.ToLower(language)
+	if found {
+		locale += "-" + strings.ToUpper(region)
+	}
+	return locale, localePattern.MatchString(locale)
+}
+
+// locales returns the fallback chain of translations to read for the
+// caller: every accepted locale followed by its language, up to the default
+// locale, whose content is stored on the products and categories themselves.
+func (u *UseCase) locales(ctx context.Context) []string {
+	var chain []string
+	add := func(locale string) {
+		if !slices.Contains(chain, locale) {
+			chain = append(chain, locale)
+		}
+	}
+
+	for _, accepted := range reqctx.Locales(ctx) {
+		locale, ok := normalizeLocale(accepted)
+		if !ok {
+			continue
+		}
+		language, _, _ := strings.Cut(locale, "-")
+		if locale == u.cfg.Localization.DefaultLocale || language == u.cfg.Localization.DefaultLocale {
+			break
+		}
+		add(locale)
+		add(language)
+	}
+	return chain
+}
+
+func (u *UseCase) validateTranslation(translation *models2.Translation) error {
+	locale, ok := normalizeLocale(translation.Locale)
+	if !ok {
+		return fmt.Errorf("invalid locale %q", translation.Locale)
+	}
+	if locale == u.cfg.Localization.DefaultLocale {
+		return fmt.Errorf("content in the default locale %s is stored on the item itself", locale)
+	}
+	if strings.TrimSpace(translation.Name) == "" {
+		return fmt.Errorf("translated name must not be empty")
+	}
+	translation.Locale = locale
+	return nil
+}
+
+func (u *UseCase) SetProductTranslation(ctx context.Context, input *models2.SetProductTranslationInput) (*models2.TranslationOutput, error) {
+	if err := u.validateTranslation(&input.Translation); err != nil {
+		return nil, err
+	}
+
+	translation, err := u.repo.SetProductTranslation(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting product translation: %v", err)
+		return nil, err
+	}
+
+	return &models2.TranslationOutput{
+		Translation: translation,
+	}, nil
+}
+
+func (u *UseCase) DeleteProductTranslation(ctx context.Context, productID int64, locale string) error {
+	locale, ok := normalizeLocale(locale)
+	if !ok {
+		return fmt.Errorf("invalid locale %q", locale)
+	}
+
+	err := u.repo.DeleteProductTranslation(ctx, productID, locale)
+	if err != nil {
+		u.logger.Errorf("Error deleting product translation: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetProductTranslations(ctx context.Context, productID int64) (*models2.GetTranslationsOutput, error) {
+	translations, err := u.repo.GetProductTranslations(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product translations: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetTranslationsOutput{
+		Translations: translations,
+	}, nil
+}
+
+func (u *UseCase) SetCategoryTranslation(ctx context.Context, input *models2.SetCategoryTranslationInput) (*models2.TranslationOutput, error) {
+	if err := u.validateTranslation(&input.Translation); err != nil {
+		return nil, err
+	}
+
+	translation, err := u.repo.SetCategoryTranslation(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error setting category translation: %v", err)
+		return nil, err
+	}
+
+	return &models2.TranslationOutput{
+		Translation: translation,
+	}, nil
+}
+
+func (u *UseCase) DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error {
+	locale, ok := normalizeLocale(locale)
+	if !ok {
+		return fmt.Errorf("invalid locale %q", locale)
+	}
+
+	err := u.repo.DeleteCategoryTranslation(ctx, categoryID, locale)
+	if err != nil {
+		u.logger.Errorf("Error deleting category translation: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetCategoryTranslations(ctx context.Context, categoryID int64) (*models2.GetTranslationsOutput, error) {
+	translations, err := u.repo.GetCategoryTranslations(ctx, categoryID)
+	if err != nil {
+		u.logger.Errorf("Error fetching category translations: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetTranslationsOutput{
+		Translations: translations,
+	}, nil
+}
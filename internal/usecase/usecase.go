@@ -1,27 +1,41 @@
 package usecase
 
 import (
+	"fmt"
 	productsv1 "github.com/Lineblaze/products_protos/gen/go/products"
 	"golang.org/x/net/context"
+	"products/config"
 	repository "products/internal"
 	models2 "products/internal/models"
 	"products/pkg/logger"
+	"products/pkg/reqctx"
+	"slices"
+	"time"
 )
 
 //go:generate ifacemaker -f *.go -o ../usecase.go -i UseCase -s UseCase -p internal -y "Controller describes methods, implemented by the usecase package."
 type UseCase struct {
+	cfg    *config.Config
 	repo   repository.Postgres
 	logger *logger.ApiLogger
+	// thumbnails is nil when no thumbnail service is configured.
+	thumbnails ThumbnailGenerator
 }
 
-func NewUseCase(repo repository.Postgres, logger *logger.ApiLogger) *UseCase {
+func NewUseCase(cfg *config.Config, repo repository.Postgres, thumbnails ThumbnailGenerator, logger *logger.ApiLogger) *UseCase {
 	return &UseCase{
-		repo:   repo,
-		logger: logger,
+		cfg:        cfg,
+		repo:       repo,
+		logger:     logger,
+		thumbnails: thumbnails,
 	}
 }
 
 func (u *UseCase) CreateProductCategory(ctx context.Context, input *models2.CreateProductCategoryInput) (*models2.CreateProductCategoryOutput, error) {
+	if err := validateSlug(input.Slug); err != nil {
+		return nil, err
+	}
+
 	category, err := u.repo.CreateProductCategory(ctx, input)
 	if err != nil {
 		u.logger.Errorf("Error creating product category: %v", err)
@@ -37,23 +51,43 @@ func (u *UseCase) CreateProductCategory(ctx context.Context, input *models2.Crea
 	}, nil
 }
 
-func (u *UseCase) GetProductCategory(ctx context.Context, id int64) (*models2.GetProductCategoryOutput, error) {
-	category, err := u.repo.GetProductCategory(ctx, id)
+func (u *UseCase) GetProductCategory(ctx context.Context, input *models2.GetProductCategoryInput) (*models2.GetProductCategoryOutput, error) {
+	if input.IncludeDeleted {
+		if err := checkStaffRead(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if input.Locales == nil {
+		input.Locales = u.locales(ctx)
+	}
+
+	category, err := u.repo.GetProductCategory(ctx, input)
 	if err != nil {
 		u.logger.Errorf("Error fetching product category: %v", err)
 		return nil, err
 	}
 
+	slug, err := u.repo.GetCategorySlug(ctx, category.Id)
+	if err != nil {
+		u.logger.Errorf("Error fetching category slug: %v", err)
+		return nil, err
+	}
+
 	return &models2.GetProductCategoryOutput{
 		Category: &productsv1.ProductCategory{
 			Id:          category.Id,
 			Name:        category.Name,
 			Description: category.Description,
 		},
+		Slug: slug,
 	}, nil
 }
 
 func (u *UseCase) UpdateProductCategory(ctx context.Context, input *models2.UpdateProductCategoryInput) (*models2.UpdateProductCategoryOutput, error) {
+	if err := validateSlug(input.Slug); err != nil {
+		return nil, err
+	}
+
 	category, err := u.repo.UpdateProductCategory(ctx, input)
 	if err != nil {
 		u.logger.Errorf("Error updating product category: %v", err)
@@ -69,17 +103,64 @@ func (u *UseCase) UpdateProductCategory(ctx context.Context, input *models2.Upda
 	}, nil
 }
 
-func (u *UseCase) DeleteProductCategory(ctx context.Context, id int64) error {
-	err := u.repo.DeleteProductCategory(ctx, id)
+func (u *UseCase) DeleteProductCategory(ctx context.Context, input *models2.DeleteProductCategoryInput) (*models2.DeleteProductCategoryOutput, error) {
+	if input.Policy == "" {
+		input.Policy = models2.CategoryDeletePolicyRestrict
+	}
+	if input.Policy == models2.CategoryDeletePolicyReassign {
+		if input.ReassignToID == 0 {
+			return nil, fmt.Errorf("reassign policy requires a target category")
+		}
+		if input.ReassignToID == input.ID {
+			return nil, fmt.Errorf("cannot reassign products to the deleted category")
+		}
+	}
+	if input.Policy == models2.CategoryDeletePolicyArchive {
+		input.ArchiveFrom = transitionSources(models2.ProductStatusArchived, reqctx.Role(ctx))
+	}
+
+	affected, err := u.repo.DeleteProductCategory(ctx, input)
 	if err != nil {
 		u.logger.Errorf("Error deleting product category: %v", err)
-		return err
+		return nil, err
 	}
-	return nil
+
+	return &models2.DeleteProductCategoryOutput{
+		AffectedProducts: affected,
+	}, nil
 }
 
-func (u *UseCase) GetProductCategories(ctx context.Context) (*models2.GetProductCategoriesOutput, error) {
-	categories, err := u.repo.GetProductCategories(ctx)
+func (u *UseCase) RestoreProductCategory(ctx context.Context, id int64) (*models2.GetProductCategoryOutput, error) {
+	if err := checkStaffRestore(ctx); err != nil {
+		return nil, err
+	}
+
+	category, err := u.repo.RestoreProductCategory(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error restoring product category: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductCategoryOutput{
+		Category: &productsv1.ProductCategory{
+			Id:          category.Id,
+			Name:        category.Name,
+			Description: category.Description,
+		},
+	}, nil
+}
+
+func (u *UseCase) GetProductCategories(ctx context.Context, input *models2.GetProductCategoriesInput) (*models2.GetProductCategoriesOutput, error) {
+	if input.IncludeDeleted {
+		if err := checkStaffRead(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if input.Locales == nil {
+		input.Locales = u.locales(ctx)
+	}
+
+	categories, err := u.repo.GetProductCategories(ctx, input)
 	if err != nil {
 		u.logger.Errorf("Error fetching product categories: %v", err)
 		return nil, err
@@ -100,6 +181,19 @@ func (u *UseCase) GetProductCategories(ctx context.Context) (*models2.GetProduct
 }
 
 func (u *UseCase) CreateProduct(ctx context.Context, input *models2.CreateProductInput) (*models2.CreateProductOutput, error) {
+	if err := u.validateProductAttributes(ctx, input.CategoryID, input.Attributes); err != nil {
+		return nil, err
+	}
+	if err := u.checkBrand(ctx, input.BrandID); err != nil {
+		return nil, err
+	}
+	if err := validateAvailabilityWindow(input.AvailabilityWindow); err != nil {
+		return nil, err
+	}
+	if err := validateSlug(input.Slug); err != nil {
+		return nil, err
+	}
+
 	product, err := u.repo.CreateProduct(ctx, input)
 	if err != nil {
 		u.logger.Errorf("Error creating product: %v", err)
@@ -117,26 +211,154 @@ func (u *UseCase) CreateProduct(ctx context.Context, input *models2.CreateProduc
 	}, nil
 }
 
-func (u *UseCase) GetProduct(ctx context.Context, id int64) (*models2.GetProductOutput, error) {
-	product, err := u.repo.GetProduct(ctx, id)
+func (u *UseCase) GetProduct(ctx context.Context, input *models2.GetProductInput) (*models2.GetProductOutput, error) {
+	if input.IncludeDeleted || input.IncludeUnpublished {
+		if err := checkStaffRead(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	group, err := u.customerGroup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkProductVisible(ctx, group, input.ID); err != nil {
+		return nil, err
+	}
+	if input.PriceList, err = u.customerGroupPriceList(ctx, group, input.PriceList, input.Currency); err != nil {
+		return nil, err
+	}
+	if input.Locales == nil {
+		input.Locales = u.locales(ctx)
+	}
+
+	var product *productsv1.Product
+	if input.AsOf.IsZero() {
+		product, err = u.repo.GetProduct(ctx, input)
+	} else {
+		product, err = u.repo.GetProductAsOf(ctx, input)
+	}
 	if err != nil {
 		u.logger.Errorf("Error fetching product: %v", err)
 		return nil, err
 	}
 
+	bundle, err := u.repo.GetBundle(ctx, product.Id)
+	if err != nil {
+		u.logger.Errorf("Error fetching bundle: %v", err)
+		return nil, err
+	}
+
+	// An earlier version keeps the base price it had unless a price list,
+	// a currency or the components price it.
+	price := product.Price
+	currency := u.cfg.Pricing.BaseCurrency
+	if input.AsOf.IsZero() || input.PriceList != "" || input.Currency != "" || (bundle != nil && bundle.Pricing == models2.BundlePricingComponents) {
+		productPrice, err := u.GetProductPrice(ctx, &models2.GetProductPriceInput{
+			ProductID: product.Id,
+			PriceList: input.PriceList,
+			Currency:  input.Currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+		price = float32(productPrice.Price)
+		currency = productPrice.Currency
+	}
+
+	brandID, err := u.repo.GetProductBrandID(ctx, product.Id)
+	if err != nil {
+		u.logger.Errorf("Error fetching product brand: %v", err)
+		return nil, err
+	}
+	availabilityWindow, err := u.repo.GetProductAvailabilityWindow(ctx, product.Id)
+	if err != nil {
+		u.logger.Errorf("Error fetching product availability window: %v", err)
+		return nil, err
+	}
+	slug, err := u.repo.GetProductSlug(ctx, product.Id)
+	if err != nil {
+		u.logger.Errorf("Error fetching product slug: %v", err)
+		return nil, err
+	}
+
+	var variants []*models2.Variant
+	if input.IncludeVariants {
+		variants, err = u.repo.GetProductVariants(ctx, product.Id)
+		if err != nil {
+			u.logger.Errorf("Error fetching product variants: %v", err)
+			return nil, err
+		}
+	}
+
+	var related []*models2.RelatedProduct
+	if input.IncludeRelated {
+		if related, err = u.relatedProducts(ctx, product.Id, group, input); err != nil {
+			return nil, err
+		}
+	}
+
 	return &models2.GetProductOutput{
 		Product: &productsv1.Product{
 			Id:          product.Id,
 			Name:        product.Name,
 			Description: product.Description,
-			Price:       product.Price,
+			Price:       price,
 			CategoryId:  product.CategoryId,
 		},
+		BrandID:            brandID,
+		AvailabilityWindow: *availabilityWindow,
+		Currency:           currency,
+		Variants:           variants,
+		Related:            related,
+		Slug:               slug,
 	}, nil
 }
 
 func (u *UseCase) UpdateProduct(ctx context.Context, input *models2.UpdateProductInput) (*models2.UpdateProductOutput, error) {
-	product, err := u.repo.UpdateProduct(ctx, input)
+	attributes := input.Attributes
+	if attributes == nil {
+		var err error
+		attributes, err = u.repo.GetProductAttributes(ctx, input.ID)
+		if err != nil {
+			u.logger.Errorf("Error fetching product attributes: %v", err)
+			return nil, err
+		}
+	}
+	if err := u.validateProductAttributes(ctx, input.CategoryID, attributes); err != nil {
+		return nil, err
+	}
+	if input.BrandID != nil {
+		if err := u.checkBrand(ctx, *input.BrandID); err != nil {
+			return nil, err
+		}
+	}
+	if input.AvailabilityWindow != nil {
+		if err := validateAvailabilityWindow(*input.AvailabilityWindow); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateSlug(input.Slug); err != nil {
+		return nil, err
+	}
+	var from models2.ProductStatus
+	if input.Status != "" {
+		if !slices.Contains(models2.ProductStatuses, input.Status) {
+			return nil, fmt.Errorf("unknown product status %q", input.Status)
+		}
+		var err error
+		if from, err = u.repo.GetProductStatus(ctx, input.ID); err != nil {
+			u.logger.Errorf("Error fetching product status: %v", err)
+			return nil, err
+		}
+		if from != input.Status {
+			if err = checkProductTransition(from, input.Status, reqctx.Role(ctx)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	product, err := u.repo.UpdateProduct(ctx, input, from)
 	if err != nil {
 		u.logger.Errorf("Error updating product: %v", err)
 		return nil, err
@@ -162,25 +384,71 @@ func (u *UseCase) DeleteProduct(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (u *UseCase) GetProducts(ctx context.Context) (*models2.GetProductsOutput, error) {
-	products, err := u.repo.GetProducts(ctx)
+func (u *UseCase) RestoreProduct(ctx context.Context, id int64) (*models2.GetProductOutput, error) {
+	if err := checkStaffRestore(ctx); err != nil {
+		return nil, err
+	}
+
+	product, err := u.repo.RestoreProduct(ctx, id)
 	if err != nil {
-		u.logger.Errorf("Error fetching products: %v", err)
+		u.logger.Errorf("Error restoring product: %v", err)
 		return nil, err
 	}
 
-	var productList []*productsv1.Product
-	for _, product := range products {
-		productList = append(productList, &productsv1.Product{
+	return &models2.GetProductOutput{
+		Product: &productsv1.Product{
 			Id:          product.Id,
 			Name:        product.Name,
 			Description: product.Description,
 			Price:       product.Price,
 			CategoryId:  product.CategoryId,
+		},
+	}, nil
+}
+
+func (u *UseCase) GetProducts(ctx context.Context, input *models2.GetProductsInput) (*models2.GetProductsOutput, error) {
+	for _, status := range input.Statuses {
+		if !slices.Contains(models2.ProductStatuses, status) {
+			return nil, fmt.Errorf("unknown product status %q", status)
+		}
+	}
+	if err := checkProductFilterRead(ctx, &input.ProductFilter); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.scopeProductFilter(ctx, &input.ProductFilter); err != nil {
+		return nil, err
+	}
+	if input.Locales == nil {
+		input.Locales = u.locales(ctx)
+	}
+
+	products, err := u.repo.GetProducts(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error fetching products: %v", err)
+		return nil, err
+	}
+
+	output := &models2.GetProductsOutput{
+		Products: products,
+	}
+	if input.Facets != nil {
+		output.Facets, err = u.GetProductFacets(ctx, &models2.GetProductFacetsInput{
+			Filter:      input.ProductFilter,
+			FacetsInput: *input.Facets,
 		})
+		if err != nil {
+			return nil, err
+		}
 	}
+	return output, nil
+}
 
-	return &models2.GetProductsOutput{
-		Products: productList,
-	}, nil
+func (u *UseCase) PurgeDeleted(ctx context.Context, retention time.Duration) (*models2.PurgeDeletedOutput, error) {
+	output, err := u.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		u.logger.Errorf("Error purging deleted items: %v", err)
+		return nil, err
+	}
+	return output, nil
 }
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	models2 "products/internal/models"
+	"strings"
+)
+
+func (u *UseCase) SetProductOptionAxes(ctx context.Context, input *models2.SetProductOptionAxesInput) (*models2.GetProductOptionAxesOutput, error) {
+	seen := make(map[string]bool, len(input.Axes))
+	for i, axis := range input.Axes {
+		axis = strings.TrimSpace(axis)
+		if axis == "" {
+			return nil, fmt.Errorf("option axis name must not be empty")
+		}
+		if seen[axis] {
+			return nil, fmt.Errorf("duplicate option axis %q", axis)
+		}
+		seen[axis] = true
+		input.Axes[i] = axis
+	}
+
+	if err := u.repo.SetProductOptionAxes(ctx, input); err != nil {
+		u.logger.Errorf("Error setting product option axes: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductOptionAxesOutput{
+		Axes: input.Axes,
+	}, nil
+}
+
+func (u *UseCase) GetProductOptionAxes(ctx context.Context, productID int64) (*models2.GetProductOptionAxesOutput, error) {
+	axes, err := u.repo.GetProductOptionAxes(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product option axes: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductOptionAxesOutput{
+		Axes: axes,
+	}, nil
+}
+
+func (u *UseCase) CreateVariant(ctx context.Context, input *models2.CreateVariantInput) (*models2.VariantOutput, error) {
+	if err := u.validateVariant(ctx, input.ProductID, input.SKU, input.Price, input.Options); err != nil {
+		return nil, err
+	}
+	if input.Attributes == nil {
+		input.Attributes = map[string]any{}
+	}
+
+	variant, err := u.repo.CreateVariant(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error creating variant: %v", err)
+		return nil, err
+	}
+
+	return &models2.VariantOutput{
+		Variant: variant,
+	}, nil
+}
+
+func (u *UseCase) GetVariant(ctx context.Context, id int64) (*models2.VariantOutput, error) {
+	variant, err := u.repo.GetVariant(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error fetching variant: %v", err)
+		return nil, err
+	}
+
+	return &models2.VariantOutput{
+		Variant: variant,
+	}, nil
+}
+
+func (u *UseCase) UpdateVariant(ctx context.Context, input *models2.UpdateVariantInput) (*models2.VariantOutput, error) {
+	current, err := u.repo.GetVariant(ctx, input.ID)
+	if err != nil {
+		u.logger.Errorf("Error fetching variant: %v", err)
+		return nil, err
+	}
+	if err = u.validateVariant(ctx, current.ProductID, input.SKU, input.Price, input.Options); err != nil {
+		return nil, err
+	}
+	if input.Attributes == nil {
+		input.Attributes = map[string]any{}
+	}
+
+	variant, err := u.repo.UpdateVariant(ctx, input)
+	if err != nil {
+		u.logger.Errorf("Error updating variant: %v", err)
+		return nil, err
+	}
+
+	return &models2.VariantOutput{
+		Variant: variant,
+	}, nil
+}
+
+func (u *UseCase) DeleteVariant(ctx context.Context, id int64) error {
+	err := u.repo.DeleteVariant(ctx, id)
+	if err != nil {
+		u.logger.Errorf("Error deleting variant: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *UseCase) GetProductVariants(ctx context.Context, productID int64) (*models2.GetProductVariantsOutput, error) {
+	variants, err := u.repo.GetProductVariants(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product variants: %v", err)
+		return nil, err
+	}
+
+	return &models2.GetProductVariantsOutput{
+		Variants: variants,
+	}, nil
+}
+
+// validateVariant checks that options name exactly the product's option axes.
+func (u *UseCase) validateVariant(ctx context.Context, productID int64, sku string, price *float64, options map[string]string) error {
+	if strings.TrimSpace(sku) == "" {
+		return fmt.Errorf("sku must not be empty")
+	}
+	if price != nil && *price < 0 {
+		return fmt.Errorf("variant price must not be negative")
+	}
+
+	axes, err := u.repo.GetProductOptionAxes(ctx, productID)
+	if err != nil {
+		u.logger.Errorf("Error fetching product option axes: %v", err)
+		return err
+	}
+	if len(axes) == 0 {
+		return fmt.Errorf("product has no option axes")
+	}
+	if len(options) != len(axes) {
+		return fmt.Errorf("variant must set exactly the options %s", strings.Join(axes, ", "))
+	}
+	for _, axis := range axes {
+		if strings.TrimSpace(options[axis]) == "" {
+			return fmt.Errorf("variant option %q must be set", axis)
+		}
+	}
+	return nil
+}
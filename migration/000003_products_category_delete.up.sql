@@ -0,0 +1,5 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_category_id_fkey;
+ALTER TABLE products
+    ADD CONSTRAINT products_category_id_fkey FOREIGN KEY (category_id) REFERENCES product_categories (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS products_category_id_idx ON products (category_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS products_deleted_at_idx;
+DROP INDEX IF EXISTS product_categories_deleted_at_idx;
+
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+DELETE FROM product_categories WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE product_categories DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE product_categories ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS product_categories_deleted_at_idx ON product_categories (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS products_deleted_at_idx ON products (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS products_revision_trg ON products;
+DROP FUNCTION IF EXISTS products_write_revision();
+DROP TABLE IF EXISTS product_revisions;
@@ -0,0 +1,35 @@
+CREATE TABLE product_revisions
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    revision BIGINT NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (product_id, revision)
+);
+
+CREATE INDEX product_revisions_product_created_idx ON product_revisions (product_id, created_at);
+
+CREATE FUNCTION products_write_revision() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD IS NOT DISTINCT FROM NEW THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO product_revisions (product_id, revision, snapshot)
+    SELECT NEW.id, COALESCE(MAX(revision), 0) + 1, to_jsonb(NEW)
+    FROM product_revisions
+    WHERE product_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_revision_trg
+    AFTER INSERT OR UPDATE ON products
+    FOR EACH ROW
+EXECUTE FUNCTION products_write_revision();
+
+INSERT INTO product_revisions (product_id, revision, snapshot)
+SELECT p.id, 1, to_jsonb(p)
+FROM products p;
@@ -0,0 +1,21 @@
+CREATE TABLE product_option_axes
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    position INT NOT NULL,
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE product_variants
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    sku TEXT NOT NULL,
+    price DECIMAL(10, 2),
+    options JSONB NOT NULL,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    CONSTRAINT product_variants_sku_key UNIQUE (sku),
+    CONSTRAINT product_variants_options_key UNIQUE (product_id, options),
+    CHECK (price IS NULL OR price >= 0)
+);
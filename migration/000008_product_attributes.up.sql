@@ -0,0 +1,19 @@
+CREATE TABLE category_attributes
+(
+    id BIGSERIAL PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES product_categories (id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('string', 'number', 'enum', 'boolean')),
+    unit TEXT,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    min_value DOUBLE PRECISION,
+    max_value DOUBLE PRECISION,
+    enum_values TEXT[],
+    CONSTRAINT category_attributes_code_key UNIQUE (category_id, code),
+    CHECK (min_value IS NULL OR max_value IS NULL OR min_value <= max_value)
+);
+
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX products_attributes_idx ON products USING GIN (attributes);
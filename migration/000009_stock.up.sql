@@ -0,0 +1,39 @@
+CREATE TABLE warehouses
+(
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    CONSTRAINT warehouses_code_key UNIQUE (code)
+);
+
+CREATE TABLE stock_levels
+(
+    product_id BIGINT NOT NULL,
+    warehouse_id BIGINT NOT NULL,
+    on_hand BIGINT NOT NULL DEFAULT 0,
+    reserved BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (product_id, warehouse_id),
+    CONSTRAINT stock_levels_product_id_fkey FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    CONSTRAINT stock_levels_warehouse_id_fkey FOREIGN KEY (warehouse_id) REFERENCES warehouses (id),
+    CHECK (reserved >= 0),
+    CHECK (on_hand >= reserved)
+);
+
+CREATE INDEX stock_levels_warehouse_idx ON stock_levels (warehouse_id);
+
+CREATE TABLE stock_movements
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    warehouse_id BIGINT NOT NULL,
+    delta BIGINT NOT NULL,
+    reason TEXT NOT NULL CHECK (reason IN ('receipt', 'sale', 'return', 'damage', 'correction', 'reservation_commit')),
+    reference TEXT,
+    actor TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (product_id, warehouse_id) REFERENCES stock_levels (product_id, warehouse_id) ON DELETE CASCADE
+);
+
+CREATE INDEX stock_movements_product_idx ON stock_movements (product_id, created_at);
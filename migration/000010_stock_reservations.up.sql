@@ -0,0 +1,20 @@
+CREATE TABLE stock_reservations
+(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status TEXT NOT NULL DEFAULT 'held' CHECK (status IN ('held', 'committed', 'released', 'expired')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX stock_reservations_held_expiry_idx ON stock_reservations (expires_at) WHERE status = 'held';
+
+CREATE TABLE stock_reservation_items
+(
+    reservation_id UUID NOT NULL REFERENCES stock_reservations (id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    warehouse_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, product_id, warehouse_id),
+    FOREIGN KEY (product_id, warehouse_id) REFERENCES stock_levels (product_id, warehouse_id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS low_stock_alerts;
+DROP TABLE IF EXISTS category_reorder_thresholds;
+DROP TABLE IF EXISTS product_reorder_thresholds;
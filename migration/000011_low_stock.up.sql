@@ -0,0 +1,21 @@
+CREATE TABLE product_reorder_thresholds
+(
+    product_id BIGINT PRIMARY KEY REFERENCES products (id) ON DELETE CASCADE,
+    threshold BIGINT NOT NULL CHECK (threshold >= 0)
+);
+
+CREATE TABLE category_reorder_thresholds
+(
+    category_id BIGINT PRIMARY KEY REFERENCES product_categories (id) ON DELETE CASCADE,
+    threshold BIGINT NOT NULL CHECK (threshold >= 0)
+);
+
+CREATE TABLE low_stock_alerts
+(
+    product_id BIGINT NOT NULL,
+    warehouse_id BIGINT NOT NULL,
+    available BIGINT NOT NULL,
+    threshold BIGINT NOT NULL,
+    alerted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (product_id, warehouse_id)
+);
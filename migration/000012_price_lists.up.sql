@@ -0,0 +1,30 @@
+CREATE TABLE price_lists
+(
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    base_price_list_id BIGINT REFERENCES price_lists (id),
+    rounding_mode TEXT NOT NULL DEFAULT 'none' CHECK (rounding_mode IN ('none', 'nearest', 'up', 'down')),
+    rounding_increment NUMERIC(12, 2) NOT NULL DEFAULT 0.01 CHECK (rounding_increment > 0),
+    price_ending NUMERIC(3, 2) CHECK (price_ending > 0 AND price_ending < 1),
+    CONSTRAINT price_lists_code_key UNIQUE (code),
+    CHECK (base_price_list_id IS NULL OR base_price_list_id <> id)
+);
+
+CREATE TABLE price_list_prices
+(
+    price_list_id BIGINT NOT NULL REFERENCES price_lists (id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    price NUMERIC(12, 2) NOT NULL CHECK (price >= 0),
+    PRIMARY KEY (price_list_id, product_id)
+);
+
+CREATE TABLE exchange_rates
+(
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(18, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (base_currency, quote_currency)
+);
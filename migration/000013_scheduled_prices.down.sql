@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS price_list_prices_history_trg ON price_list_prices;
+DROP TRIGGER IF EXISTS products_price_history_trg ON products;
+DROP FUNCTION IF EXISTS write_price_history();
+DROP TABLE IF EXISTS price_history;
+DROP TABLE IF EXISTS scheduled_prices;
@@ -0,0 +1,79 @@
+CREATE TABLE scheduled_prices
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    price_list_id BIGINT REFERENCES price_lists (id) ON DELETE CASCADE,
+    price NUMERIC(12, 2) NOT NULL CHECK (price >= 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'finished', 'cancelled')),
+    previous_price NUMERIC(12, 2),
+    applied_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX scheduled_prices_product_idx ON scheduled_prices (product_id, starts_at);
+CREATE INDEX scheduled_prices_pending_idx ON scheduled_prices (starts_at) WHERE status = 'pending';
+CREATE INDEX scheduled_prices_active_idx ON scheduled_prices (ends_at) WHERE status = 'active';
+
+CREATE TABLE price_history
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    price_list_id BIGINT,
+    old_price NUMERIC(12, 2),
+    new_price NUMERIC(12, 2),
+    scheduled_price_id BIGINT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX price_history_product_idx ON price_history (product_id, changed_at);
+
+-- The scheduler sets products.scheduled_price_id for the transaction so that
+-- history rows can point at the entry that caused them.
+CREATE FUNCTION write_price_history() RETURNS TRIGGER AS
+$$
+DECLARE
+    scheduled_id BIGINT := NULLIF(current_setting('products.scheduled_price_id', true), '')::BIGINT;
+BEGIN
+    IF TG_TABLE_NAME = 'products' THEN
+        IF TG_OP = 'INSERT' OR OLD.price IS DISTINCT FROM NEW.price THEN
+            INSERT INTO price_history (product_id, old_price, new_price, scheduled_price_id)
+            VALUES (NEW.id, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, scheduled_id);
+        END IF;
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO price_history (product_id, price_list_id, old_price, new_price, scheduled_price_id)
+        VALUES (OLD.product_id, OLD.price_list_id, OLD.price, NULL, scheduled_id);
+        RETURN OLD;
+    END IF;
+
+    IF TG_OP = 'INSERT' OR OLD.price IS DISTINCT FROM NEW.price THEN
+        INSERT INTO price_history (product_id, price_list_id, old_price, new_price, scheduled_price_id)
+        VALUES (NEW.product_id, NEW.price_list_id, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, scheduled_id);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_price_history_trg
+    AFTER INSERT OR UPDATE OF price ON products
+    FOR EACH ROW
+EXECUTE FUNCTION write_price_history();
+
+CREATE TRIGGER price_list_prices_history_trg
+    AFTER INSERT OR UPDATE OR DELETE ON price_list_prices
+    FOR EACH ROW
+EXECUTE FUNCTION write_price_history();
+
+INSERT INTO price_history (product_id, new_price)
+SELECT id, price
+FROM products;
+
+INSERT INTO price_history (product_id, price_list_id, new_price)
+SELECT product_id, price_list_id, price
+FROM price_list_prices;
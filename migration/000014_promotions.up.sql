@@ -0,0 +1,24 @@
+CREATE TABLE promotions
+(
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('percent', 'fixed', 'buy_x_get_y')),
+    value NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (value >= 0),
+    buy_quantity INT NOT NULL DEFAULT 0 CHECK (buy_quantity >= 0),
+    get_quantity INT NOT NULL DEFAULT 0 CHECK (get_quantity >= 0),
+    currency CHAR(3),
+    category_ids BIGINT[] NOT NULL DEFAULT '{}',
+    product_ids BIGINT[] NOT NULL DEFAULT '{}',
+    min_price NUMERIC(12, 2) CHECK (min_price >= 0),
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    priority INT NOT NULL DEFAULT 0,
+    stacking TEXT NOT NULL DEFAULT 'stackable' CHECK (stacking IN ('exclusive', 'stackable')),
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (kind <> 'percent' OR value <= 100),
+    CHECK (kind <> 'buy_x_get_y' OR (buy_quantity > 0 AND get_quantity > 0)),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX promotions_active_idx ON promotions (priority DESC, id) WHERE active;
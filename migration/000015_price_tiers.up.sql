@@ -0,0 +1,8 @@
+CREATE TABLE price_tiers
+(
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    min_quantity BIGINT NOT NULL CHECK (min_quantity > 0),
+    max_quantity BIGINT CHECK (max_quantity >= min_quantity),
+    unit_price NUMERIC(12, 2) NOT NULL CHECK (unit_price >= 0),
+    PRIMARY KEY (product_id, min_quantity)
+);
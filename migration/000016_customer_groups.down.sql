@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS customer_group_products;
+ALTER TABLE products DROP COLUMN IF EXISTS restricted;
+DROP TABLE IF EXISTS customer_groups;
@@ -0,0 +1,21 @@
+CREATE TABLE customer_groups
+(
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    price_list_id BIGINT REFERENCES price_lists (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT customer_groups_code_key UNIQUE (code)
+);
+
+-- A restricted product is visible only to the groups listed for it.
+ALTER TABLE products ADD COLUMN restricted BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE customer_group_products
+(
+    group_id BIGINT NOT NULL REFERENCES customer_groups (id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, product_id)
+);
+
+CREATE INDEX customer_group_products_product_idx ON customer_group_products (product_id);
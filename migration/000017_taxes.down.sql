@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_regions;
+DROP TABLE IF EXISTS category_tax_classes;
+DROP TABLE IF EXISTS product_tax_classes;
+DROP TABLE IF EXISTS tax_classes;
@@ -0,0 +1,36 @@
+CREATE TABLE tax_classes
+(
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    CONSTRAINT tax_classes_code_key UNIQUE (code)
+);
+
+CREATE TABLE product_tax_classes
+(
+    product_id BIGINT PRIMARY KEY REFERENCES products (id) ON DELETE CASCADE,
+    tax_class_id BIGINT NOT NULL REFERENCES tax_classes (id) ON DELETE CASCADE
+);
+
+CREATE TABLE category_tax_classes
+(
+    category_id BIGINT PRIMARY KEY REFERENCES product_categories (id) ON DELETE CASCADE,
+    tax_class_id BIGINT NOT NULL REFERENCES tax_classes (id) ON DELETE CASCADE
+);
+
+CREATE TABLE tax_regions
+(
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    display_gross BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE TABLE tax_rates
+(
+    id BIGSERIAL PRIMARY KEY,
+    tax_class_id BIGINT NOT NULL REFERENCES tax_classes (id) ON DELETE CASCADE,
+    region_code TEXT NOT NULL REFERENCES tax_regions (code) ON DELETE CASCADE,
+    rate NUMERIC(7, 4) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    effective_from TIMESTAMPTZ NOT NULL,
+    CONSTRAINT tax_rates_class_region_from_key UNIQUE (tax_class_id, region_code, effective_from)
+);
@@ -0,0 +1,22 @@
+CREATE TABLE media_thumbnails
+(
+    ref TEXT PRIMARY KEY,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE product_media
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL DEFAULT 'image' CHECK (kind IN ('image')),
+    url TEXT NOT NULL,
+    alt_text TEXT NOT NULL DEFAULT '',
+    position INT NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    thumbnail_ref TEXT REFERENCES media_thumbnails (ref),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT product_media_position_key UNIQUE (product_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE UNIQUE INDEX product_media_primary_idx ON product_media (product_id) WHERE is_primary;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS products_brand_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS brand_id;
+DROP TABLE IF EXISTS brands;
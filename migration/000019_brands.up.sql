@@ -0,0 +1,13 @@
+CREATE TABLE brands
+(
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX brands_name_key ON brands (lower(name)) WHERE deleted_at IS NULL;
+
+ALTER TABLE products ADD COLUMN brand_id BIGINT REFERENCES brands (id) ON DELETE RESTRICT;
+
+CREATE INDEX products_brand_id_idx ON products (brand_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_status_transitions;
+DROP INDEX IF EXISTS products_status_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS status;
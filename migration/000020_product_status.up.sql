@@ -0,0 +1,22 @@
+-- Existing products stay live; new products start as drafts.
+ALTER TABLE products
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'in_review', 'published', 'discontinued', 'archived'));
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX products_status_idx ON products (status);
+
+CREATE TABLE product_status_transitions
+(
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor TEXT,
+    role TEXT,
+    reason TEXT NOT NULL DEFAULT '',
+    request_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX product_status_transitions_product_idx ON product_status_transitions (product_id, created_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS products_available_until_idx;
+DROP INDEX IF EXISTS products_available_from_idx;
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_availability_check,
+    DROP COLUMN IF EXISTS available_until,
+    DROP COLUMN IF EXISTS available_from;
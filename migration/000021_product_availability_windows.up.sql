@@ -0,0 +1,7 @@
+ALTER TABLE products
+    ADD COLUMN available_from TIMESTAMPTZ,
+    ADD COLUMN available_until TIMESTAMPTZ,
+    ADD CONSTRAINT products_availability_check CHECK (available_from < available_until);
+
+CREATE INDEX products_available_from_idx ON products (available_from) WHERE available_from IS NOT NULL;
+CREATE INDEX products_available_until_idx ON products (available_until) WHERE available_until IS NOT NULL;
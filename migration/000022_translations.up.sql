@@ -0,0 +1,19 @@
+-- Translations of product and category content. The name and description
+-- columns of the base tables hold the default-locale content.
+CREATE TABLE product_translations
+(
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (product_id, locale)
+);
+
+CREATE TABLE category_translations
+(
+    category_id BIGINT NOT NULL REFERENCES product_categories (id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (category_id, locale)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS category_slug_redirects;
+DROP TABLE IF EXISTS product_slug_redirects;
+ALTER TABLE product_categories DROP COLUMN IF EXISTS slug;
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
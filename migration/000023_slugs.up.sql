@@ -0,0 +1,65 @@
+-- Backfills slugs with the transliteration of pkg/slug.Make; accented Latin
+-- letters are not handled here and are dropped like other characters.
+CREATE FUNCTION pg_temp.slugify(value TEXT) RETURNS TEXT LANGUAGE sql IMMUTABLE AS $$
+SELECT trim(BOTH '-' FROM left(regexp_replace(
+    translate(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(lower(value), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'), 'ш', 'sh'), 'щ', 'shch'), 'ю', 'yu'), 'я', 'ya'), 'є', 'ye'), 'ї', 'yi'),
+        'абвгдеёзийклмнопрстуфыэіґъь',
+        'abvgdeeziyklmnoprstufyeig'),
+    '[^a-z0-9]+', '-', 'g'), 80))
+$$;
+
+-- assign_slugs gives every row of a table a free slug the way the service
+-- does: the slug of the name, or the entity name when the name has no usable
+-- characters, followed by -2, -3, ... until no other row has it.
+CREATE FUNCTION pg_temp.assign_slugs(entities REGCLASS, entity TEXT) RETURNS VOID LANGUAGE plpgsql AS $$
+DECLARE
+    item RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INT;
+    taken BOOLEAN;
+BEGIN
+    FOR item IN EXECUTE format('SELECT id, name FROM %s ORDER BY id', entities) LOOP
+        base := coalesce(nullif(pg_temp.slugify(item.name), ''), entity);
+        candidate := base;
+        n := 2;
+        LOOP
+            EXECUTE format('SELECT EXISTS (SELECT 1 FROM %s WHERE slug = $1)', entities) INTO taken USING candidate;
+            EXIT WHEN NOT taken;
+            candidate := base || '-' || n;
+            n := n + 1;
+        END LOOP;
+        EXECUTE format('UPDATE %s SET slug = $1 WHERE id = $2', entities) USING candidate, item.id;
+    END LOOP;
+END
+$$;
+
+-- The unique constraints come first so that the lookups above use their
+-- indexes; they allow the NULL slugs of the rows not assigned yet.
+ALTER TABLE products ADD COLUMN slug TEXT, ADD CONSTRAINT products_slug_key UNIQUE (slug);
+ALTER TABLE product_categories ADD COLUMN slug TEXT, ADD CONSTRAINT product_categories_slug_key UNIQUE (slug);
+
+SELECT pg_temp.assign_slugs('products', 'product');
+SELECT pg_temp.assign_slugs('product_categories', 'category');
+
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE product_categories ALTER COLUMN slug SET NOT NULL;
+
+-- Former slugs keep resolving after a rename.
+CREATE TABLE product_slug_redirects
+(
+    slug TEXT PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX product_slug_redirects_product_idx ON product_slug_redirects (product_id);
+
+CREATE TABLE category_slug_redirects
+(
+    slug TEXT PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES product_categories (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX category_slug_redirects_category_idx ON category_slug_redirects (category_id);
@@ -0,0 +1,16 @@
+CREATE TABLE barcodes
+(
+    id BIGSERIAL PRIMARY KEY,
+    -- gtin is the code padded to 14 digits, so UPC-A and EAN-13 forms of one
+    -- item collide.
+    gtin CHAR(14) NOT NULL,
+    code TEXT NOT NULL,
+    format TEXT NOT NULL CHECK (format IN ('ean8', 'upc_a', 'ean13', 'gtin14')),
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    variant_id BIGINT REFERENCES product_variants (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT barcodes_gtin_key UNIQUE (gtin)
+);
+
+CREATE INDEX barcodes_product_idx ON barcodes (product_id);
+CREATE INDEX barcodes_variant_idx ON barcodes (variant_id) WHERE variant_id IS NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE bundles
+(
+    product_id BIGINT PRIMARY KEY REFERENCES products (id) ON DELETE CASCADE,
+    pricing TEXT NOT NULL CHECK (pricing IN ('fixed', 'components')),
+    -- discount_percent is taken off the sum of the component prices.
+    discount_percent DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (discount_percent >= 0 AND discount_percent < 100)
+);
+
+CREATE TABLE bundle_components
+(
+    bundle_id BIGINT NOT NULL REFERENCES bundles (product_id) ON DELETE CASCADE,
+    component_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    position INT NOT NULL,
+    PRIMARY KEY (bundle_id, component_id),
+    CHECK (bundle_id <> component_id)
+);
+
+CREATE INDEX bundle_components_component_idx ON bundle_components (component_id);
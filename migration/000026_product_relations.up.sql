@@ -0,0 +1,11 @@
+CREATE TABLE product_relations
+(
+    product_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    related_id BIGINT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('accessory', 'replacement', 'upsell', 'cross_sell')),
+    position INT NOT NULL,
+    PRIMARY KEY (product_id, type, related_id),
+    CHECK (product_id <> related_id)
+);
+
+CREATE INDEX product_relations_related_idx ON product_relations (related_id);
@@ -0,0 +1,5 @@
+DROP FUNCTION IF EXISTS product_price(BIGINT, BIGINT, CHAR(3), BIGINT);
+DROP FUNCTION IF EXISTS price_in_list(BIGINT, BIGINT, CHAR(3), BIGINT, INT);
+DROP FUNCTION IF EXISTS base_unit_price(BIGINT, BIGINT);
+DROP FUNCTION IF EXISTS exchange_rate(CHAR(3), CHAR(3));
+DROP FUNCTION IF EXISTS apply_price_rounding(NUMERIC, TEXT, NUMERIC, NUMERIC);
@@ -0,0 +1,119 @@
+-- product_price resolves the price of a product for the service
+-- (usecase.GetProductPrice) as well as for listings, price filters and price
+-- facets, so that they all agree to the cent.
+
+CREATE FUNCTION apply_price_rounding(price NUMERIC, mode TEXT, increment NUMERIC, ending NUMERIC) RETURNS NUMERIC
+    LANGUAGE plpgsql IMMUTABLE AS
+$$
+DECLARE
+    steps NUMERIC;
+    rounded NUMERIC;
+BEGIN
+    IF mode = 'none' THEN
+        RETURN round(price, 2);
+    END IF;
+
+    steps := price / increment;
+    steps := CASE mode
+        WHEN 'nearest' THEN round(steps)
+        WHEN 'up' THEN ceil(steps - 1e-9)
+        WHEN 'down' THEN floor(steps + 1e-9)
+    END;
+    rounded := steps * increment;
+    IF ending > 0 THEN
+        rounded := rounded - 1 + ending;
+    END IF;
+    RETURN round(rounded, 2);
+END
+$$;
+
+CREATE FUNCTION exchange_rate(base CHAR(3), quote CHAR(3)) RETURNS NUMERIC
+    LANGUAGE plpgsql STABLE AS
+$$
+DECLARE
+    result NUMERIC;
+BEGIN
+    SELECT rate INTO result FROM (
+        SELECT rate, 0 AS preference FROM exchange_rates WHERE base_currency = base AND quote_currency = quote
+        UNION ALL
+        SELECT 1 / rate, 1 FROM exchange_rates WHERE base_currency = quote AND quote_currency = base
+    ) rates ORDER BY preference LIMIT 1;
+    IF result IS NULL THEN
+        RAISE EXCEPTION 'no exchange rate from % to %', base, quote;
+    END IF;
+    RETURN result;
+END
+$$;
+
+-- base_unit_price is the unit price of a product for a quantity: the price of
+-- the tier covering the quantity, or the product price without one.
+CREATE FUNCTION base_unit_price(product BIGINT, quantity BIGINT) RETURNS NUMERIC
+    LANGUAGE sql STABLE AS
+$$
+    SELECT COALESCE((SELECT t.unit_price FROM price_tiers t
+                     WHERE t.product_id = product AND t.min_quantity <= quantity
+                       AND (t.max_quantity IS NULL OR t.max_quantity >= quantity)
+                     ORDER BY t.min_quantity DESC LIMIT 1), p.price)
+    FROM products p WHERE p.id = product
+$$;
+
+-- price_in_list returns the list's own price or converts the price of its
+-- base, so a list price takes precedence over the tiers.
+CREATE FUNCTION price_in_list(product BIGINT, list_id BIGINT, base_currency CHAR(3), quantity BIGINT, depth INT DEFAULT 0) RETURNS NUMERIC
+    LANGUAGE plpgsql STABLE AS
+$$
+DECLARE
+    list price_lists;
+    price NUMERIC;
+    currency CHAR(3);
+BEGIN
+    SELECT * INTO list FROM price_lists WHERE id = list_id;
+    IF depth > 10 THEN
+        RAISE EXCEPTION 'price list "%" has too many base lists', list.code;
+    END IF;
+
+    SELECT lp.price INTO price FROM price_list_prices lp WHERE lp.price_list_id = list_id AND lp.product_id = product;
+    IF FOUND THEN
+        RETURN price;
+    END IF;
+
+    IF list.base_price_list_id IS NOT NULL THEN
+        price := price_in_list(product, list.base_price_list_id, base_currency, quantity, depth + 1);
+        SELECT b.currency INTO currency FROM price_lists b WHERE b.id = list.base_price_list_id;
+    ELSE
+        price := base_unit_price(product, quantity);
+        currency := base_currency;
+    END IF;
+
+    IF currency <> list.currency THEN
+        price := apply_price_rounding(price * exchange_rate(currency, list.currency),
+            list.rounding_mode, list.rounding_increment, list.price_ending);
+    END IF;
+    RETURN price;
+END
+$$;
+
+-- product_price prices a unit of a product bought in a quantity, in a price
+-- list, or at its base price when list_id is NULL. Listings price a single
+-- unit, so they use the tier starting at quantity 1. Bundles priced from
+-- their components sum the component prices less the bundle discount.
+CREATE FUNCTION product_price(product BIGINT, list_id BIGINT, base_currency CHAR(3), quantity BIGINT DEFAULT 1) RETURNS NUMERIC
+    LANGUAGE plpgsql STABLE AS
+$$
+DECLARE
+    discount NUMERIC;
+    total NUMERIC;
+BEGIN
+    SELECT b.discount_percent INTO discount FROM bundles b WHERE b.product_id = product AND b.pricing = 'components';
+    IF FOUND THEN
+        SELECT SUM(product_price(c.component_id, list_id, base_currency, c.quantity * product_price.quantity) * c.quantity) INTO total
+        FROM bundle_components c WHERE c.bundle_id = product;
+        RETURN round(total * (1 - discount / 100), 2);
+    END IF;
+
+    IF list_id IS NULL THEN
+        RETURN base_unit_price(product, quantity);
+    END IF;
+    RETURN price_in_list(product, list_id, base_currency, quantity);
+END
+$$;
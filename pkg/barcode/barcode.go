@@ -0,0 +1,70 @@
+package barcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatEAN8   Format = "ean8"
+	FormatUPCA   Format = "upc_a"
+	FormatEAN13  Format = "ean13"
+	FormatGTIN14 Format = "gtin14"
+)
+
+var formats = map[int]Format{
+	8:  FormatEAN8,
+	12: FormatUPCA,
+	13: FormatEAN13,
+	14: FormatGTIN14,
+}
+
+// Barcode is a parsed GS1 barcode. GTIN is the code left-padded to 14 digits,
+// under which a UPC-A and the EAN-13 with a leading zero are the same item.
+type Barcode struct {
+	Code   string
+	Format Format
+	GTIN   string
+}
+
+// Parse detects the format of a GTIN-8, UPC-A, EAN-13 or GTIN-14 code from
+// its length and verifies its check digit. Spaces and hyphens are ignored.
+func Parse(code string) (*Barcode, error) {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return nil, fmt.Errorf("barcode %q must contain only digits", code)
+		}
+	}
+	format, ok := formats[len(code)]
+	if !ok {
+		return nil, fmt.Errorf("barcode %q has %d digits; expected 8, 12, 13 or 14", code, len(code))
+	}
+
+	last := len(code) - 1
+	if expected := CheckDigit(code[:last]); code[last] != expected {
+		return nil, fmt.Errorf("barcode %q has invalid check digit %c; expected %c", code, code[last], expected)
+	}
+
+	return &Barcode{
+		Code:   code,
+		Format: format,
+		GTIN:   strings.Repeat("0", 14-len(code)) + code,
+	}, nil
+}
+
+// CheckDigit computes the GS1 mod-10 check digit of the digits that precede
+// it: weighting from the right alternates 3 and 1.
+func CheckDigit(digits string) byte {
+	sum := 0
+	for i := range len(digits) {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
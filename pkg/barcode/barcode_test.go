@@ -0,0 +1,79 @@
+package barcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		format Format
+		gtin   string
+		err    string
+	}{
+		{name: "EAN-8", code: "96385074", format: FormatEAN8, gtin: "00000096385074"},
+		{name: "UPC-A", code: "036000291452", format: FormatUPCA, gtin: "00036000291452"},
+		{name: "EAN-13", code: "4006381333931", format: FormatEAN13, gtin: "04006381333931"},
+		{name: "EAN-13 with leading zero", code: "0036000291452", format: FormatEAN13, gtin: "00036000291452"},
+		{name: "GTIN-14", code: "10012345000017", format: FormatGTIN14, gtin: "10012345000017"},
+		{name: "spaces and hyphens", code: "4006381 33393-1", format: FormatEAN13, gtin: "04006381333931"},
+
+		{name: "EAN-8 bad check digit", code: "96385075", err: "invalid check digit 5; expected 4"},
+		{name: "UPC-A bad check digit", code: "036000291453", err: "invalid check digit 3; expected 2"},
+		{name: "EAN-13 bad check digit", code: "4006381333932", err: "invalid check digit 2; expected 1"},
+		{name: "GTIN-14 bad check digit", code: "10012345000018", err: "invalid check digit 8; expected 7"},
+		{name: "letters", code: "40063813339A1", err: "must contain only digits"},
+		{name: "too short", code: "1234567", err: "has 7 digits"},
+		{name: "between formats", code: "12345678901", err: "has 11 digits"},
+		{name: "too long", code: "123456789012345", err: "has 15 digits"},
+		{name: "empty", code: "", err: "has 0 digits"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse(tt.code)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				assert.Nil(t, parsed)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, parsed.Format)
+			assert.Equal(t, tt.gtin, parsed.GTIN)
+		})
+	}
+}
+
+// A UPC-A and the EAN-13 made of it with a leading zero identify the same
+// item, so they must share a GTIN.
+func TestParseUPCAAndEAN13Collide(t *testing.T) {
+	upc, err := Parse("036000291452")
+	require.NoError(t, err)
+	ean, err := Parse("0036000291452")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, upc.Format, ean.Format)
+	assert.NotEqual(t, upc.Code, ean.Code)
+	assert.Equal(t, upc.GTIN, ean.GTIN)
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"9638507", '4'},
+		{"03600029145", '2'},
+		{"400638133393", '1'},
+		{"590123412345", '7'},
+		{"1001234500001", '7'},
+		{"0000000", '0'},
+		{"", '0'},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, string(tt.want), string(CheckDigit(tt.digits)), tt.digits)
+	}
+}
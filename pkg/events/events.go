@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"products/pkg/logger"
+	"time"
+)
+
+type Event struct {
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Payload    any       `json:"payload"`
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to the service log. It stands in until a
+// message broker is wired up.
+type LogPublisher struct {
+	logger *logger.ApiLogger
+}
+
+func NewLogPublisher(logger *logger.ApiLogger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, event Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.logger.Infof("Event: %s", b)
+	return nil
+}
@@ -0,0 +1,63 @@
+package reqctx
+
+import "context"
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	requestIDKey
+	customerGroupKey
+	roleKey
+	localesKey
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the caller identity attached to ctx, or an empty string.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// Role returns the caller's role for permission checks, or an empty string.
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey).(string)
+	return role
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithCustomerGroup(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, customerGroupKey, code)
+}
+
+// CustomerGroup returns the code of the caller's customer group, or an empty
+// string for callers outside any group.
+func CustomerGroup(ctx context.Context) string {
+	code, _ := ctx.Value(customerGroupKey).(string)
+	return code
+}
+
+func WithLocales(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, localesKey, locales)
+}
+
+// Locales returns the locales the caller accepts, most preferred first.
+func Locales(ctx context.Context) []string {
+	locales, _ := ctx.Value(localesKey).([]string)
+	return locales
+}
@@ -0,0 +1,70 @@
+package slug
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MaxLength is the length generated slugs are cut to.
+const MaxLength = 80
+
+// pattern matches valid slugs: lowercase ASCII words joined by single hyphens.
+var pattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// cyrillic transliterates Russian and Ukrainian letters to Latin.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+}
+
+// Make builds a URL-safe slug from s: Cyrillic is transliterated, accents are
+// dropped from Latin letters, letters are lowercased and every other run of
+// characters becomes a single hyphen. The result is empty when s has no
+// letters or digits that can be kept.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFC.String(strings.ToLower(s)) {
+		var part string
+		if latin, ok := cyrillic[r]; ok {
+			part = latin
+		} else if r = unaccent(r); r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			part = string(r)
+		} else {
+			hyphen = b.Len() > 0
+			continue
+		}
+		if part == "" {
+			continue
+		}
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > MaxLength {
+		slug = strings.TrimRight(slug[:MaxLength], "-")
+	}
+	return slug
+}
+
+// unaccent maps a letter such as é to its base letter e.
+func unaccent(r rune) rune {
+	for _, base := range norm.NFD.String(string(r)) {
+		return base
+	}
+	return r
+}
+
+// Valid reports whether s is a well-formed slug.
+func Valid(s string) bool {
+	return len(s) <= MaxLength && pattern.MatchString(s)
+}
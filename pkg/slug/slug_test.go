@@ -0,0 +1,60 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "latin", s: "iPhone 15 Pro Max", want: "iphone-15-pro-max"},
+		{name: "punctuation", s: "  Hello, World! ", want: "hello-world"},
+		{name: "accents", s: "Crème Brûlée", want: "creme-brulee"},
+		{name: "russian", s: "Чайник электрический", want: "chaynik-elektricheskiy"},
+		{name: "multi-letter transliteration", s: "Щётка для обуви", want: "shchetka-dlya-obuvi"},
+		{name: "hard and soft signs", s: "Подъезд и пальто", want: "podezd-i-palto"},
+		{name: "ukrainian", s: "Ґанок їжака Євген", want: "ganok-yizhaka-yevgen"},
+		{name: "mixed scripts", s: "Кабель USB-C 2м", want: "kabel-usb-c-2m"},
+		{name: "no usable characters", s: "---", want: ""},
+		{name: "other scripts dropped", s: "日本", want: ""},
+		{name: "cut to the maximum length", s: strings.Repeat("a", 79) + " b c", want: strings.Repeat("a", 79)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Make(tt.s)
+			assert.Equal(t, tt.want, got)
+			if got != "" {
+				assert.True(t, Valid(got), "Make must return a valid slug")
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{name: "words", s: "hello-world-2", want: true},
+		{name: "maximum length", s: strings.Repeat("a", MaxLength), want: true},
+		{name: "empty", s: ""},
+		{name: "uppercase", s: "Hello"},
+		{name: "double hyphen", s: "a--b"},
+		{name: "leading hyphen", s: "-a"},
+		{name: "trailing hyphen", s: "a-"},
+		{name: "cyrillic", s: "чайник"},
+		{name: "too long", s: strings.Repeat("a", MaxLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Valid(tt.s))
+		})
+	}
+}
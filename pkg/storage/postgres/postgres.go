@@ -23,6 +23,7 @@ const (
 
 type Postgres interface {
 	Stats() *pgxpool.Stat
+	Begin(ctx context.Context) (pgx.Tx, error)
 	Query(query string, args ...any) (pgx.Rows, error)
 	QueryContext(ctx context.Context, query string, args ...any) (pgx.Rows, error)
 	Get(dest interface{}, query string, args ...interface{}) error
@@ -71,6 +72,11 @@ func InitPsqlDB(c *config.Config) (Postgres, error) {
 	return &Pool{db: result}, nil
 }
 
+// NewPool wraps an existing connection pool.
+func NewPool(db *pgxpool.Pool) Postgres {
+	return &Pool{db: db}
+}
+
 func (p Pool) Stats() *pgxpool.Stat {
 	return p.db.Stat()
 }
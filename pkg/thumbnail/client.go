@@ -0,0 +1,86 @@
+package thumbnail
+
+import (
+	"context"
+	"fmt"
+	thumbnailv1 "github.com/Lineblaze/thumbnail_protos/gen/go/thumbnail"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"products/config"
+	"time"
+)
+
+// Client generates thumbnails through the thumbnail service. Every attempt
+// has its own timeout, and transient failures are retried with exponential
+// backoff.
+type Client struct {
+	conn    *grpc.ClientConn
+	client  thumbnailv1.ThumbnailClient
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+}
+
+func NewClient(cfg *config.Config) (*Client, error) {
+	conn, err := grpc.NewClient(cfg.Thumbnail.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail service connection: %w", err)
+	}
+
+	return &Client{
+		conn:    conn,
+		client:  thumbnailv1.NewThumbnailClient(conn),
+		timeout: cfg.Thumbnail.Timeout,
+		retries: cfg.Thumbnail.Retries,
+		backoff: cfg.Thumbnail.RetryBackoff,
+	}, nil
+}
+
+// Generate returns the thumbnail of the image at url.
+func (c *Client) Generate(ctx context.Context, url string) ([]byte, error) {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		thumbnail, err := c.generate(ctx, url)
+		if err == nil {
+			return thumbnail, nil
+		}
+		if attempt >= c.retries || !retryable(err) {
+			return nil, fmt.Errorf("thumbnail generation failed after %d attempts: %w", attempt+1, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) generate(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	response, err := c.client.GetThumbnail(ctx, &thumbnailv1.ThumbnailRequest{Url: url})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.GetThumbnail()) == 0 {
+		return nil, fmt.Errorf("thumbnail service returned an empty thumbnail")
+	}
+	return response.GetThumbnail(), nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package thumbnail
+
+import (
+	"context"
+	"products/config"
+	"products/pkg/thumbnail/fake"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestClient(t *testing.T, retries int) (*Client, *fake.Server) {
+	t.Helper()
+
+	server, err := fake.Start()
+	require.NoError(t, err)
+	t.Cleanup(server.Stop)
+
+	var cfg config.Config
+	cfg.Thumbnail.Address = server.Address()
+	cfg.Thumbnail.Timeout = 100 * time.Millisecond
+	cfg.Thumbnail.Retries = retries
+	cfg.Thumbnail.RetryBackoff = time.Millisecond
+	client, err := NewClient(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client, server
+}
+
+func TestGenerateRetriesTransientFailures(t *testing.T) {
+	client, server := newTestClient(t, 3)
+	server.FailNext(2, codes.Unavailable)
+
+	thumbnail, err := client.Generate(context.Background(), "https://example.com/a.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "thumbnail:https://example.com/a.jpg", string(thumbnail))
+	assert.Len(t, server.Calls(), 3)
+}
+
+func TestGenerateGivesUpAfterRetries(t *testing.T) {
+	client, server := newTestClient(t, 2)
+	server.FailNext(5, codes.ResourceExhausted)
+
+	_, err := client.Generate(context.Background(), "https://example.com/a.jpg")
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.Len(t, server.Calls(), 3)
+}
+
+func TestGenerateDoesNotRetryPermanentFailures(t *testing.T) {
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.Internal} {
+		t.Run(code.String(), func(t *testing.T) {
+			client, server := newTestClient(t, 3)
+			server.FailNext(1, code)
+
+			_, err := client.Generate(context.Background(), "https://example.com/a.jpg")
+			require.Error(t, err)
+			assert.Equal(t, code, status.Code(err))
+			assert.ErrorContains(t, err, "after 1 attempts")
+			assert.Len(t, server.Calls(), 1)
+		})
+	}
+}
+
+func TestGenerateTimesOutEachAttempt(t *testing.T) {
+	client, server := newTestClient(t, 1)
+	server.DelayNext(1, 10*time.Second)
+
+	start := time.Now()
+	thumbnail, err := client.Generate(context.Background(), "https://example.com/a.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "thumbnail:https://example.com/a.jpg", string(thumbnail))
+	assert.Len(t, server.Calls(), 2)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGenerateReportsTimeout(t *testing.T) {
+	client, server := newTestClient(t, 0)
+	server.DelayNext(1, 10*time.Second)
+
+	_, err := client.Generate(context.Background(), "https://example.com/a.jpg")
+	require.Error(t, err)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Len(t, server.Calls(), 1)
+}
+
+func TestGenerateStopsWhenCanceled(t *testing.T) {
+	client, server := newTestClient(t, 3)
+	client.backoff = time.Hour
+	server.FailNext(1, codes.Unavailable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Generate(ctx, "https://example.com/a.jpg")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, server.Calls(), 1)
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want bool
+	}{
+		{codes.Unavailable, true},
+		{codes.DeadlineExceeded, true},
+		{codes.ResourceExhausted, true},
+		{codes.Aborted, true},
+		{codes.InvalidArgument, false},
+		{codes.NotFound, false},
+		{codes.PermissionDenied, false},
+		{codes.Internal, false},
+		{codes.Unknown, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, retryable(status.Error(tt.code, "failure")), tt.code.String())
+	}
+}
@@ -0,0 +1,106 @@
+// Package fake runs an in-process thumbnail service for local development and tests.
+package fake
+
+import (
+	"context"
+	thumbnailv1 "github.com/Lineblaze/thumbnail_protos/gen/go/thumbnail"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"sync"
+	"time"
+)
+
+// Server answers GetThumbnail with "thumbnail:" followed by the requested URL.
+// It can be told to fail or delay a number of calls to exercise client
+// retries and timeouts.
+type Server struct {
+	thumbnailv1.UnimplementedThumbnailServer
+
+	grpcServer *grpc.Server
+	listener   net.Listener
+
+	mu       sync.Mutex
+	failures int
+	code     codes.Code
+	delays   int
+	delay    time.Duration
+	calls    []string
+}
+
+// Start listens on a random local port and serves until Stop is called.
+func Start() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{grpcServer: grpc.NewServer(), listener: listener}
+	thumbnailv1.RegisterThumbnailServer(s.grpcServer, s)
+	go s.grpcServer.Serve(listener)
+	return s, nil
+}
+
+// Address is the host:port to dial.
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// FailNext makes the next n calls fail with the given code.
+func (s *Server) FailNext(n int, code codes.Code) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+	s.code = code
+}
+
+// DelayNext makes the next n calls wait for d before answering, or until the
+// caller gives up.
+func (s *Server) DelayNext(n int, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delays = n
+	s.delay = d
+}
+
+// Calls returns the URLs requested so far, including failed calls.
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+func (s *Server) Stop() {
+	s.grpcServer.Stop()
+}
+
+func (s *Server) GetThumbnail(ctx context.Context, req *thumbnailv1.ThumbnailRequest) (*thumbnailv1.ThumbnailResponse, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, req.GetUrl())
+	var delay time.Duration
+	if s.delays > 0 {
+		s.delays--
+		delay = s.delay
+	}
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-time.After(delay):
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return nil, status.Error(s.code, "fake thumbnail failure")
+	}
+	if req.GetUrl() == "" {
+		return nil, status.Error(codes.InvalidArgument, "url is required")
+	}
+	return &thumbnailv1.ThumbnailResponse{Thumbnail: []byte("thumbnail:" + req.GetUrl())}, nil
+}